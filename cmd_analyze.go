@@ -0,0 +1,150 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/thinkeng/liuyao/pkg"
+)
+
+// analysisOutput analyze 子命令的 JSON 输出
+type analysisOutput struct {
+	Chart    *chart              `json:"chart"`
+	Category string              `json:"category"`
+	Gender   string              `json:"gender"`
+	Analysis *pkg.AnalysisResult `json:"analysis,omitempty"`
+	Error    string              `json:"error,omitempty"`
+}
+
+// parseGender 规范化性别参数
+func parseGender(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case "male", "m", "男":
+		return "Male", nil
+	case "female", "f", "女":
+		return "Female", nil
+	}
+	return "", fmt.Errorf("无效的性别 %q, 应为 Male 或 Female", s)
+}
+
+// parseCategory 按标识或中文名解析求测事项, 返回其标识
+func parseCategory(s string) (string, error) {
+	if ci, ok := pkg.LookupCategory(s); ok {
+		return ci.ID, nil
+	}
+	return "", fmt.Errorf("无效的求测事项 %q, 可选: %s", s, strings.Join(pkg.CategoryIDs(), ", "))
+}
+
+// runAnalyze 起卦、排盘并解卦
+func runAnalyze(args []string) error {
+	var f chartFlags
+	var category, gender, profile, tuChangSheng, liangXian, relation string
+	var yongShenLine int
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	f.register(fs)
+	fs.StringVar(&f.guaci, "guaci", "卦辞.md", "卦辞 Markdown 文件路径")
+	fs.StringVar(&category, "category", pkg.CategoryMarriage, "求测事项: "+strings.Join(pkg.CategoryIDs(), ", ")+" (亦可用中文名)")
+	fs.StringVar(&gender, "gender", "Female", "求测者性别: Male 或 Female")
+	fs.StringVar(&profile, "profile", "default", "旺衰计分方案: "+strings.Join(pkg.ProfileIDs(), ", ")+", 或 YAML/JSON 文件路径")
+	fs.StringVar(&tuChangSheng, "tu-changsheng", "申", "土长生取法: 申 (水土同宫) 或 巳 (火土同宫)")
+	fs.IntVar(&yongShenLine, "yongshen-line", 0, "指定用神爻位 1-6 (自初爻起), 0 为按事项自动选取")
+	fs.StringVar(&relation, "relation", "self", "事主关系 (代占): self, spouse, child, parent, sibling, friend, boss, other, 或中文如 子女")
+	fs.StringVar(&liangXian, "liangxian", "default", "用神两现取舍法: default (持世、发动、临月日、临应) 或 kongpo (先取旬空月破者)")
+	fs.Parse(args)
+
+	if err := f.validateFormat(); err != nil {
+		return err
+	}
+	category, err := parseCategory(category)
+	if err != nil {
+		return err
+	}
+	gender, err = parseGender(gender)
+	if err != nil {
+		return err
+	}
+	scoring, err := pkg.ResolveProfile(profile)
+	if err != nil {
+		return err
+	}
+	school, err := pkg.ParseChangShengSchool(tuChangSheng)
+	if err != nil {
+		return err
+	}
+	liangXianRule, err := pkg.ParseLiangXianRule(liangXian)
+	if err != nil {
+		return err
+	}
+	subject, err := pkg.ParseRelation(relation)
+	if err != nil {
+		return err
+	}
+	date, err := f.castTime()
+	if err != nil {
+		return err
+	}
+	cast, err := f.buildGua(date)
+	if err != nil {
+		return err
+	}
+	if err := loadGuaCi(f.guaci, false); err != nil {
+		return err
+	}
+
+	c, err := buildChart(cast, date)
+	if err != nil {
+		return err
+	}
+
+	analysisCtx := pkg.NewAnalysisContext(c.Gua, date, category, gender)
+	analysisCtx.Profile = scoring
+	analysisCtx.ChangShengSchool = school
+	analysisCtx.YongShenLine = yongShenLine
+	analysisCtx.LiangXian = liangXianRule
+	analysisCtx.Relation = subject
+	analysisResult, analysisErr := pkg.Analyze(analysisCtx)
+
+	if f.format == "json" {
+		out := analysisOutput{Chart: c, Category: category, Gender: gender}
+		if analysisErr != nil {
+			out.Error = analysisErr.Error()
+		} else {
+			out.Analysis = &analysisResult
+		}
+		return printJSON(out)
+	}
+
+	printChart(c)
+
+	// Hexagram Analysis
+	fmt.Println("\n====================================")
+	fmt.Println("开始解卦 (Hexagram Analysis)...")
+	fmt.Println("====================================")
+	fmt.Printf("设定求测事项: %s (性别: %s)\n", category, gender)
+
+	if analysisErr != nil {
+		return fmt.Errorf("解卦失败: %v", analysisErr)
+	}
+
+	fmt.Println(pkg.GenerateReport(analysisResult))
+
+	// Display Text Info (Gua & Yao)
+	guaText, _, err := pkg.QueryGuaAndYaoCi(analysisResult.GuaName, "")
+	if err != nil {
+		return nil
+	}
+	fmt.Println("================动爻卦辞====================")
+	fmt.Println(strings.Repeat("-", 40))
+	fmt.Printf("【卦名】: %s %s (%s)\n", guaText.Name, guaText.Hexagram, guaText.Alias)
+	fmt.Printf("【卦辞】: %s\n", guaText.GuaCi)
+
+	for _, yao := range analysisResult.MovingYaos {
+		fmt.Println(strings.Repeat("-", 20))
+		fmt.Printf("【动爻】: %s (变 %s)\n", yao.YaoName, yao.BianGuaName)
+		fmt.Printf("【本爻辞】: %s\n", yao.BenYaoCi)
+		fmt.Printf("【爻动含义】: %s\n", yao.YaoDongHanYi)
+	}
+	fmt.Println(strings.Repeat("-", 40))
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/thinkeng/liuyao/pkg"
+)
+
+// calendarOutput calendar 子命令的输出
+type calendarOutput struct {
+	Date    time.Time `json:"date"`
+	BaZi    string    `json:"baZi"`
+	XunKong string    `json:"xunKong"`
+	ShenSha []string  `json:"shenSha"`
+}
+
+// runCalendar 查看指定时间的四柱、旬空与神煞
+func runCalendar(args []string) error {
+	var f chartFlags
+	fs := flag.NewFlagSet("calendar", flag.ExitOnError)
+	f.registerTime(fs)
+	fs.Parse(args)
+
+	if err := f.validateFormat(); err != nil {
+		return err
+	}
+	date, err := f.castTime()
+	if err != nil {
+		return err
+	}
+
+	baZi, dayKong := pkg.GetDayGanZhi(date)
+	out := calendarOutput{
+		Date:    date,
+		BaZi:    baZi.GetYear() + " " + baZi.GetMonth() + " " + baZi.GetDay() + " " + baZi.GetTime(),
+		XunKong: dayKong,
+		ShenSha: pkg.GetShenShaConfig(baZi.GetDayGan(), baZi.GetDayZhi(), baZi.GetMonthZhi()),
+	}
+
+	if f.format == "json" {
+		return printJSON(out)
+	}
+
+	fmt.Println("公历: ", date.Format("2006-01-02 15:04 MST"))
+	fmt.Println("日期: ", out.BaZi)
+	fmt.Println("旬空: ", out.XunKong)
+	fmt.Printf("神煞: %s\n", strings.Join(out.ShenSha, " "))
+	return nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/thinkeng/liuyao/pkg"
+)
+
+// chart 一次起卦的完整排盘结果
+type chart struct {
+	Date         time.Time        `json:"date"`
+	BaZi         string           `json:"baZi"`
+	XunKong      string           `json:"xunKong"`
+	DayGan       string           `json:"dayGan"`
+	DayZhi       string           `json:"dayZhi"`
+	MonthZhi     string           `json:"monthZhi"`
+	Meihua       *pkg.MeihuaCast  `json:"meihua,omitempty"`
+	Number       *pkg.NumberCast  `json:"number,omitempty"`
+	Yarrow       []pkg.YarrowLine `json:"yarrow,omitempty"`
+	Gua          pkg.Gua          `json:"gua"`
+	Hexagram     string           `json:"hexagram"`
+	BianHexagram string           `json:"bianHexagram"`
+	GuaName      string           `json:"guaName"`
+	BianGuaName  string           `json:"bianGuaName,omitempty"`
+	ShenSha      []string         `json:"shenSha"`
+	Ben          []pkg.GuaInfo    `json:"ben"`
+	Bian         []pkg.GuaInfo    `json:"bian,omitempty"`
+}
+
+func (c *chart) hasMoving() bool {
+	for _, changed := range c.Gua.Changed {
+		if changed {
+			return true
+		}
+	}
+	return false
+}
+
+// buildChart 根据卦象与起卦时间完成排盘
+func buildChart(cast casting, date time.Time) (*chart, error) {
+	baZi, dayKong := pkg.GetDayGanZhi(date)
+	gua := cast.Gua
+
+	c := &chart{
+		Date:         date,
+		BaZi:         baZi.GetYear() + " " + baZi.GetMonth() + " " + baZi.GetDay() + " " + baZi.GetTime(),
+		XunKong:      dayKong,
+		DayGan:       baZi.GetDayGan(),
+		DayZhi:       baZi.GetDayZhi(),
+		MonthZhi:     baZi.GetMonthZhi(),
+		Meihua:       cast.Meihua,
+		Number:       cast.Number,
+		Yarrow:       cast.Yarrow,
+		Gua:          gua,
+		Hexagram:     strings.Join(gua.BenGua, ""),
+		BianHexagram: strings.Join(gua.BianGua, ""),
+	}
+
+	result, err := pkg.GetGuaInfo(c.Hexagram, c.DayGan)
+	if err != nil {
+		return nil, err
+	}
+	c.Ben = result
+	c.GuaName = pkg.GetFullGuaName(c.Hexagram)
+
+	// Display Shen Sha Config
+	c.ShenSha = pkg.GetShenShaConfig(c.DayGan, c.DayZhi, c.MonthZhi)
+
+	// Calculate Gua Shen
+	var shiPosition int
+	var isYang bool
+	for i, info := range result {
+		if info.ShiYing == "世" {
+			shiPosition = i + 1
+			if c.Hexagram[i] == '1' {
+				isYang = true
+			}
+			break
+		}
+	}
+	if guaShen := pkg.GetGuaShen(shiPosition, isYang); guaShen != "" {
+		c.ShenSha = append(c.ShenSha, fmt.Sprintf("卦身:%s", guaShen))
+	}
+
+	// Override YaoType with specific line value (Lao Yang/Lao Yin)
+	for i := range c.Ben {
+		if i < len(gua.Lines) {
+			specificType, specificName := pkg.ParseLineValue(gua.Lines[i])
+			c.Ben[i].YaoType = specificName + ":" + specificType
+		}
+	}
+
+	if c.hasMoving() {
+		palaceIndex, _, _ := pkg.GetGuaPalace(pkg.DetermineGuaName(c.Hexagram))
+		palaceWuXing := pkg.GetPalaceWuXing(palaceIndex)
+		// Use GetBianGuaInfo with Ben Gua's Palace Wu Xing
+		bianResult, err := pkg.GetBianGuaInfo(c.BianHexagram, c.DayGan, palaceWuXing)
+		if err != nil {
+			return nil, err
+		}
+		c.Bian = bianResult
+		c.BianGuaName = pkg.GetFullGuaName(c.BianHexagram)
+	}
+
+	return c, nil
+}
+
+// printChart 以文本形式打印排盘
+func printChart(c *chart) {
+	for _, v := range c.Gua.Lines {
+		fmt.Println(pkg.ParseLineValue(v))
+	}
+	if c.Gua.Tosses != nil {
+		fmt.Println(c.Gua.Tosses)
+	} else {
+		fmt.Println(c.Gua.Lines)
+	}
+	for i, line := range c.Yarrow {
+		fmt.Printf("蓍草%s: ", []string{"初", "二", "三", "四", "五", "上"}[i])
+		for _, ch := range line.Changes {
+			fmt.Printf("%d策 分%d/%d 余%d+%d 去%d; ", ch.Stalks, ch.Left, ch.Right, ch.LeftRemainder, ch.RightRemainder, ch.Removed)
+		}
+		fmt.Printf("得%d\n", line.Value)
+	}
+	if c.Gua.Source != "" {
+		fmt.Println("随机源:", c.Gua.Source)
+	}
+	if c.Meihua != nil {
+		fmt.Println("梅花起卦:", c.Meihua)
+	}
+	if c.Number != nil {
+		fmt.Println("数字起卦:", c.Number)
+	}
+
+	fmt.Println("本卦 → 变卦:", c.Hexagram, c.BianHexagram)
+	fmt.Print("动爻: [")
+	for i, dong := range c.Gua.Changed {
+		if dong {
+			fmt.Printf("%s ", []string{"初", "二", "三", "四", "五", "上"}[i])
+		}
+	}
+	fmt.Println("]")
+	fmt.Println()
+
+	fmt.Println("日期: ", c.BaZi)
+	fmt.Println("旬空: ", c.XunKong)
+
+	fmt.Printf("本卦: %s (%s) 纳甲与六神配置 (日干:%s):\n", c.GuaName, c.Hexagram, c.DayGan)
+	fmt.Printf("神煞: %s\n", strings.Join(c.ShenSha, " "))
+
+	fmt.Println("====================================")
+	fmt.Println("爻位\t六神\t六亲\t干支\t伏神    \t世应\t爻类型")
+	fmt.Println("------------------------------------")
+	for i := len(c.Ben) - 1; i >= 0; i-- {
+		info := c.Ben[i]
+		fmt.Printf("%s\t%s\t%s\t%s\t%-8s\t%s\t%s\n", info.Position, info.LiuShen, info.LiuQin, info.Ganzhi, info.FuShen, info.ShiYing, info.YaoType)
+	}
+	fmt.Println("====================================")
+
+	if c.Bian != nil {
+		fmt.Printf("\n变卦: %s (%s) 纳甲与六神配置:\n", c.BianGuaName, c.BianHexagram)
+		fmt.Println("====================================")
+		fmt.Println("爻位\t六神\t六亲\t干支\t伏神\t世应\t爻类型")
+		fmt.Println("------------------------------------")
+		for i := len(c.Bian) - 1; i >= 0; i-- {
+			info := c.Bian[i]
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\t%s\t%s\n", info.Position, info.LiuShen, info.LiuQin, info.Ganzhi, info.FuShen, info.ShiYing, info.YaoType)
+		}
+		fmt.Println("====================================")
+	}
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// runCast 起卦并排盘
+func runCast(args []string) error {
+	var f chartFlags
+	fs := flag.NewFlagSet("cast", flag.ExitOnError)
+	f.register(fs)
+	fs.Parse(args)
+
+	if err := f.validateFormat(); err != nil {
+		return err
+	}
+	date, err := f.castTime()
+	if err != nil {
+		return err
+	}
+	cast, err := f.buildGua(date)
+	if err != nil {
+		return err
+	}
+
+	c, err := buildChart(cast, date)
+	if err != nil {
+		return err
+	}
+
+	if f.format == "json" {
+		return printJSON(c)
+	}
+	printChart(c)
+	return nil
+}
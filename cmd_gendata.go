@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"os"
 	"regexp"
@@ -26,11 +27,17 @@ type GuaData struct {
 	Yaos        []YaoData
 }
 
-func main123() {
-	content, err := os.ReadFile("卦辞.md")
+// runGenData 解析卦辞 Markdown, 生成 data/guadata.go
+func runGenData(args []string) error {
+	var in, out string
+	fs := flag.NewFlagSet("gen-data", flag.ExitOnError)
+	fs.StringVar(&in, "in", "卦辞.md", "卦辞 Markdown 文件路径")
+	fs.StringVar(&out, "out", "data/guadata.go", "生成的 Go 文件路径")
+	fs.Parse(args)
+
+	content, err := os.ReadFile(in)
 	if err != nil {
-		fmt.Printf("Error reading file: %v\n", err)
-		return
+		return fmt.Errorf("Error reading file: %v", err)
 	}
 
 	lines := strings.Split(string(content), "\n")
@@ -176,10 +183,13 @@ func main123() {
 		"泰卦":  {"拔茅征吉，志在外也。", "包荒得尚于中行，以光大也。", "无往不复，天地际也。", "翩翩不富，皆失实也。", "不戒以孚，中心愿也。", "城复于隍，其命乱也。"},
 	}
 
-	outFile, _ := os.Create("data/guadata.go")
+	outFile, err := os.Create(out)
+	if err != nil {
+		return err
+	}
 	defer outFile.Close()
 	w := bufio.NewWriter(outFile)
-	fmt.Fprintln(w, "package data\n\nimport \"fmt\"\n\ntype YaoData struct {\n\tName         string\n\tYaoCi        string\n\tXiangCi      string\n\tYaoDongHanYi string\n}\n\ntype GuaData struct {\n\tName        string\n\tBinaryCode  string\n\tGuaCi       string\n\tDaXiang     string\n\tCoreMeaning string\n\tYaos        []YaoData\n}\n\nvar GuaIndex = map[string]GuaData{")
+	fmt.Fprint(w, "package data\n\nimport \"fmt\"\n\ntype YaoData struct {\n\tName         string `json:\"name\"`\n\tYaoCi        string `json:\"yaoCi\"`\n\tXiangCi      string `json:\"xiangCi\"`\n\tYaoDongHanYi string `json:\"yaoDongHanYi\"`\n}\n\ntype GuaData struct {\n\tName        string    `json:\"name\"`\n\tBinaryCode  string    `json:\"binaryCode\"`\n\tGuaCi       string    `json:\"guaCi\"`\n\tDaXiang     string    `json:\"daXiang\"`\n\tCoreMeaning string    `json:\"coreMeaning\"`\n\tYaos        []YaoData `json:\"yaos\"`\n}\n\nvar GuaIndex = map[string]GuaData{\n")
 
 	for _, name := range []string{
 		"乾为天", "天风姤", "天山遁", "天地否", "风地观", "山地剥", "火地晋", "火天大有",
@@ -208,6 +218,28 @@ func main123() {
 		}
 		fmt.Fprintln(w, "\t\t},\n\t},")
 	}
-	fmt.Fprintln(w, "}\n\nfunc GetGuaData(binary string) (GuaData, bool) {\n\tg, ok := GuaIndex[binary]\n\treturn g, ok\n}\n\nfunc (g GuaData) Print() {\n\tfmt.Printf(\"【%s】(%s)\\n\", g.Name, g.BinaryCode)\n\tfmt.Printf(\"卦辞：%s\\n\", g.GuaCi)\n\tfmt.Printf(\"大象：%s\\n\", g.DaXiang)\n\tfor _, y := range g.Yaos {\n\t\tfmt.Printf(\"  %s：%s\\n\", y.Name, y.YaoCi)\n\t\tif y.XiangCi != \"\" {\n\t\t\tfmt.Printf(\"    《象》曰：%s\\n\", y.XiangCi)\n\t\t}\n\t}\n}")
-	w.Flush()
+	w.WriteString(`}
+
+func GetGuaData(binary string) (GuaData, bool) {
+	g, ok := GuaIndex[binary]
+	return g, ok
+}
+
+func (g GuaData) Print() {
+	fmt.Printf("【%s】(%s)\n", g.Name, g.BinaryCode)
+	fmt.Printf("卦辞：%s\n", g.GuaCi)
+	fmt.Printf("大象：%s\n", g.DaXiang)
+	for _, y := range g.Yaos {
+		fmt.Printf("  %s：%s\n", y.Name, y.YaoCi)
+		if y.XiangCi != "" {
+			fmt.Printf("    《象》曰：%s\n", y.XiangCi)
+		}
+	}
+}
+`)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("已生成 %s (%d 卦)\n", out, len(allGua))
+	return nil
 }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/thinkeng/liuyao/pkg"
+)
+
+// runLookup 查询卦辞与爻辞; 给出卦名 (及爻名) 时直接查询, 否则进入交互模式
+func runLookup(args []string) error {
+	var guaci string
+	fs := flag.NewFlagSet("lookup", flag.ExitOnError)
+	fs.StringVar(&guaci, "guaci", "卦辞.md", "卦辞 Markdown 文件路径")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "用法: liuyao lookup [-guaci 文件] [卦名 [爻名]]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if err := loadGuaCi(guaci, true); err != nil {
+		return err
+	}
+
+	switch fs.NArg() {
+	case 0:
+		return lookupREPL()
+	case 1, 2:
+		return printLookup(fs.Arg(0), fs.Arg(1))
+	}
+	fs.Usage()
+	return fmt.Errorf("参数过多")
+}
+
+func printLookup(guaName, yaoName string) error {
+	gua, yao, err := pkg.QueryGuaAndYaoCi(guaName, yaoName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(strings.Repeat("-", 40))
+	fmt.Printf("【卦名】: %s %s (%s)\n", gua.Name, gua.Hexagram, gua.Alias)
+	fmt.Printf("【卦辞】: %s\n", gua.GuaCi)
+	if yaoName != "" {
+		fmt.Printf("【动爻】: %s (变 %s)\n", yao.YaoName, yao.BianGuaName)
+		fmt.Printf("【本爻辞】: %s\n", yao.BenYaoCi)
+		fmt.Printf("【爻动含义】: %s\n", yao.YaoDongHanYi)
+	}
+	fmt.Println(strings.Repeat("-", 40))
+	return nil
+}
+
+// lookupREPL 交互式查询
+func lookupREPL() error {
+	fmt.Println("✅ 易经八宫数据索引建立完成。")
+	fmt.Println(strings.Repeat("=", 60))
+
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Println("请输入要查询的卦名和动爻名（例如：坤为地 初六）。输入 '退出' 结束程序。")
+
+	for {
+		fmt.Print("\n查询> ")
+		input, err := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+
+		if input == "退出" || input == "exit" || (err != nil && input == "") {
+			fmt.Println("程序结束。")
+			return nil
+		}
+
+		parts := strings.Fields(input)
+		if len(parts) != 2 {
+			fmt.Println("输入格式错误。请按 [卦名] [动爻名] 格式输入。")
+			continue
+		}
+
+		if err := printLookup(parts[0], parts[1]); err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thinkeng/liuyao/pkg"
+)
+
+// chartFlags 排盘相关的公共参数 (cast / analyze / calendar 共用)
+type chartFlags struct {
+	date    string
+	clock   string
+	tz      string
+	method  string
+	numbers string
+	phrase  string
+	strokes string
+	addHour bool
+	seed    string
+	entropy string
+	tosses  string
+	lines   string
+	hex     string
+	moving  string
+	format  string
+	guaci   string
+}
+
+func (f *chartFlags) registerTime(fs *flag.FlagSet) {
+	fs.StringVar(&f.date, "date", "", "起卦日期 YYYY-MM-DD (默认今天)")
+	fs.StringVar(&f.clock, "time", "", "起卦时间 HH:MM (默认当前时间)")
+	fs.StringVar(&f.tz, "tz", "Local", "时区, 如 Asia/Shanghai")
+	fs.StringVar(&f.format, "format", "text", "输出格式: text 或 json")
+}
+
+func (f *chartFlags) register(fs *flag.FlagSet) {
+	f.registerTime(fs)
+	fs.StringVar(&f.method, "method", "coin", "起卦方式: coin (掷币), yarrow (蓍草), time (年月日时), number (报数), phrase (字数)")
+	fs.StringVar(&f.numbers, "numbers", "", "报数起卦所报之数, 1至3个, 逗号分隔, 如 3,8")
+	fs.StringVar(&f.phrase, "phrase", "", "字数起卦所用字句")
+	fs.StringVar(&f.strokes, "strokes", "", "字句中每个字的笔画数, 逗号分隔 (缺省时以字数计)")
+	fs.BoolVar(&f.addHour, "add-hour", false, "报数/字数起卦时加入起卦时辰数")
+	fs.StringVar(&f.seed, "seed", "", "掷币/蓍草随机种子 (可复现); 缺省使用 crypto/rand")
+	fs.StringVar(&f.entropy, "entropy", "", "掷币/蓍草熵源文件, - 表示标准输入")
+	fs.StringVar(&f.tosses, "tosses", "", "六次掷币结果, 自初爻至上爻, 逗号分隔, 如 110,111,000,100,110,001")
+	fs.StringVar(&f.lines, "lines", "", "六爻爻值 6/7/8/9, 自初爻至上爻, 如 789896 (6=老阴 7=少阳 8=少阴 9=老阳)")
+	fs.StringVar(&f.hex, "hex", "", "本卦二进制, 自初爻至上爻, 1=阳 0=阴, 如 111001")
+	fs.StringVar(&f.moving, "moving", "", "配合 -hex 使用的动爻位置 (1-6), 逗号分隔, 如 5,6")
+}
+
+// castTime 解析起卦时间, 未指定的部分取当前时间
+func (f *chartFlags) castTime() (time.Time, error) {
+	loc, err := time.LoadLocation(f.tz)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("无效的时区 %q: %v", f.tz, err)
+	}
+
+	now := time.Now().In(loc)
+	year, month, day := now.Date()
+	hour, minute := now.Hour(), now.Minute()
+
+	if f.date != "" {
+		d, err := time.ParseInLocation("2006-01-02", f.date, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("无效的日期 %q, 应为 YYYY-MM-DD", f.date)
+		}
+		year, month, day = d.Date()
+	}
+	if f.clock != "" {
+		c, err := time.ParseInLocation("15:04", f.clock, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("无效的时间 %q, 应为 HH:MM", f.clock)
+		}
+		hour, minute = c.Hour(), c.Minute()
+	}
+
+	return time.Date(year, month, day, hour, minute, 0, 0, loc), nil
+}
+
+func (f *chartFlags) validateFormat() error {
+	if f.format != "text" && f.format != "json" {
+		return fmt.Errorf("无效的输出格式 %q, 应为 text 或 json", f.format)
+	}
+	return nil
+}
+
+// casting 起卦结果及起卦过程记录
+type casting struct {
+	Gua    pkg.Gua
+	Meihua *pkg.MeihuaCast  // 梅花易数计算过程 (时间起卦时)
+	Number *pkg.NumberCast  // 报数/字数起卦计算过程
+	Yarrow []pkg.YarrowLine // 蓍草起卦每爻的三变过程
+}
+
+// buildGua 根据参数生成卦象; 未指定卦象时按起卦方式起卦
+func (f *chartFlags) buildGua(date time.Time) (casting, error) {
+	inputs := 0
+	for _, v := range []string{f.tosses, f.lines, f.hex} {
+		if v != "" {
+			inputs++
+		}
+	}
+
+	switch {
+	case inputs > 1:
+		return casting{}, fmt.Errorf("-tosses、-lines 与 -hex 只能选用其一")
+	case f.tosses != "":
+		tosses := strings.Split(f.tosses, ",")
+		if len(tosses) != 6 {
+			return casting{}, fmt.Errorf("-tosses 需要 6 组掷币结果, 实际为 %d 组", len(tosses))
+		}
+		for i, t := range tosses {
+			t = strings.TrimSpace(t)
+			if len(t) != 3 || strings.Trim(t, "01") != "" {
+				return casting{}, fmt.Errorf("第 %d 组掷币结果 %q 无效, 应为三位 0/1", i+1, t)
+			}
+			tosses[i] = t
+		}
+		return casting{Gua: pkg.GenerateGua(tosses)}, nil
+	case f.lines != "":
+		gua, err := pkg.ParseLineValues(f.lines)
+		if err != nil {
+			return casting{}, fmt.Errorf("-lines %q 无效: %v", f.lines, err)
+		}
+		return casting{Gua: gua}, nil
+	case f.hex != "":
+		gua, err := guaFromHex(f.hex, f.moving)
+		return casting{Gua: gua}, err
+	case f.moving != "":
+		return casting{}, fmt.Errorf("-moving 需与 -hex 一起使用")
+	}
+
+	switch f.method {
+	case "coin", "yarrow":
+		caster, err := f.caster()
+		if err != nil {
+			return casting{}, err
+		}
+		if closer, ok := caster.(io.Closer); ok {
+			defer closer.Close()
+		}
+		if f.method == "yarrow" {
+			gua, lines, err := pkg.CastYarrow(caster)
+			return casting{Gua: gua, Yarrow: lines}, err
+		}
+		gua, err := pkg.CastCoins(caster)
+		return casting{Gua: gua}, err
+	case "time":
+		gua, m := pkg.CastByTime(date)
+		return casting{Gua: gua, Meihua: &m}, nil
+	case "number", "phrase":
+		hour := 0
+		if f.addHour {
+			hour = pkg.HourZhiNumber(date)
+		}
+
+		var gua pkg.Gua
+		var n pkg.NumberCast
+		var err error
+		if f.method == "number" {
+			nums, perr := parseInts("-numbers", f.numbers)
+			if perr != nil {
+				return casting{}, perr
+			}
+			gua, n, err = pkg.CastByNumbers(hour, nums...)
+		} else {
+			var strokes []int
+			if f.strokes != "" {
+				if strokes, err = parseInts("-strokes", f.strokes); err != nil {
+					return casting{}, err
+				}
+			}
+			gua, n, err = pkg.CastByPhrase(f.phrase, strokes, hour)
+		}
+		if err != nil {
+			return casting{}, err
+		}
+		return casting{Gua: gua, Number: &n}, nil
+	}
+	return casting{}, fmt.Errorf("无效的起卦方式 %q, 应为 coin、yarrow、time、number 或 phrase", f.method)
+}
+
+// parseInts 解析逗号分隔的整数列表
+func parseInts(name, s string) ([]int, error) {
+	if s == "" {
+		return nil, fmt.Errorf("缺少 %s 参数", name)
+	}
+	var nums []int
+	for _, p := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			return nil, fmt.Errorf("%s 中的数 %q 无效", name, p)
+		}
+		nums = append(nums, n)
+	}
+	return nums, nil
+}
+
+// caster 根据 -seed / -entropy 选择随机源
+func (f *chartFlags) caster() (pkg.Caster, error) {
+	switch {
+	case f.seed != "" && f.entropy != "":
+		return nil, fmt.Errorf("-seed 与 -entropy 不能同时使用")
+	case f.seed != "":
+		seed, err := strconv.ParseInt(f.seed, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的种子 %q", f.seed)
+		}
+		return pkg.NewSeededCaster(seed), nil
+	case f.entropy == "-":
+		return pkg.NewReaderCaster(os.Stdin, "stdin"), nil
+	case f.entropy != "":
+		return pkg.NewFileCaster(f.entropy, 0)
+	}
+	return pkg.CryptoCaster{}, nil
+}
+
+// guaFromHex 由本卦二进制与动爻位置构造卦象
+func guaFromHex(hex, moving string) (pkg.Gua, error) {
+	if _, err := pkg.ParseHexagram(hex); err != nil {
+		return pkg.Gua{}, fmt.Errorf("-hex %q 无效: %v", hex, err)
+	}
+
+	changed := make([]bool, 6)
+	if moving != "" {
+		for _, p := range strings.Split(moving, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(p))
+			if err != nil || n < 1 || n > 6 {
+				return pkg.Gua{}, fmt.Errorf("-moving 中的爻位 %q 无效, 应为 1-6", p)
+			}
+			changed[n-1] = true
+		}
+	}
+
+	values := make([]int, 6)
+	for i := range values {
+		switch {
+		case hex[i] == '1' && changed[i]:
+			values[i] = 9
+		case hex[i] == '0' && changed[i]:
+			values[i] = 6
+		case hex[i] == '1':
+			values[i] = 7
+		default:
+			values[i] = 8
+		}
+	}
+	return pkg.GenerateGuaFromLines(values)
+}
+
+// loadGuaCi 读取卦辞文件并建立索引; 文件缺失时仅给出警告
+func loadGuaCi(filename string, required bool) error {
+	contentBytes, err := os.ReadFile(filename)
+	if err != nil {
+		if required {
+			return fmt.Errorf("文件读取失败：请确保文件【%s】存在，并包含正确的八宫Markdown内容。错误信息: %v", filename, err)
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  未能读取卦辞文件【%s】, 将不显示卦辞: %v\n", filename, err)
+		return nil
+	}
+	pkg.InitGuaCiIndex(string(contentBytes))
+	return nil
+}
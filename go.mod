@@ -3,3 +3,5 @@ module github.com/thinkeng/liuyao
 go 1.24.3
 
 require github.com/6tail/lunar-go v1.4.6
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -27,3 +28,41 @@ func TestDetermineYongShen_NewCategories(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyze_Findings(t *testing.T) {
+	// 乾为天 静卦, 问事业取官鬼: 四爻壬午, 子月冲之为月破
+	ctx := qianCareerContext()
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.YongShenIndex != 3 {
+		t.Fatalf("YongShenIndex = %d, want 3", result.YongShenIndex)
+	}
+	if !result.HasFinding(FindingYuePo, "用神") {
+		t.Errorf("expected 用神 月破 finding, got %v", result.Findings)
+	}
+
+	yuePo := result.FindingsOf(FindingYuePo)
+	if len(yuePo) != 1 || yuePo[0].Score >= 0 || yuePo[0].Direction != Unfavorable {
+		t.Errorf("unexpected 月破 finding: %+v", yuePo)
+	}
+	if len(yuePo[0].Lines) != 1 || yuePo[0].Lines[0] != 3 {
+		t.Errorf("月破 Lines = %v, want [3]", yuePo[0].Lines)
+	}
+	if got := yuePo[0].Render(); !strings.HasPrefix(got, "[月破] 用神 四爻 凶 -") {
+		t.Errorf("Render() = %q, want typed prefix", got)
+	}
+	line := Finding{Kind: FindingJue, Subject: "二爻", Lines: []int{1}, Text: "甲寅 临日绝(申)"}
+	if got, want := line.Render(), "[绝] 二爻: 甲寅 临日绝(申)"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	if got := len(result.FindingsOf(FindingLine)); got != 6 {
+		t.Errorf("expected 6 line findings, got %d", got)
+	}
+	if result.Timing == "" {
+		t.Error("expected Timing to be set")
+	}
+}
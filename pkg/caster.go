@@ -0,0 +1,188 @@
+package pkg
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Caster 起卦随机源
+// 掷币、蓍草等起卦方式都从 Caster 取随机数, 便于测试、复盘与审计。
+type Caster interface {
+	// Intn 返回 [0, n) 内的随机整数
+	Intn(n int) (int, error)
+	// Source 描述随机源, 随卦记录, 以便复现同一卦
+	Source() string
+}
+
+// SeededCaster 以固定种子驱动的伪随机源, 同一种子得到同一卦
+type SeededCaster struct {
+	seed int64
+	rng  *rand.Rand
+}
+
+// NewSeededCaster 创建以 seed 为种子的伪随机源
+func NewSeededCaster(seed int64) *SeededCaster {
+	return &SeededCaster{seed: seed, rng: rand.New(rand.NewSource(seed))}
+}
+
+func (c *SeededCaster) Intn(n int) (int, error) {
+	if n <= 0 {
+		return 0, fmt.Errorf("Intn 的参数必须为正数, 实际为%d", n)
+	}
+	return c.rng.Intn(n), nil
+}
+
+func (c *SeededCaster) Source() string {
+	return fmt.Sprintf("seed:%d", c.seed)
+}
+
+// CryptoCaster 使用 crypto/rand 的随机源 (不可复现, 但卦中保留原始掷币结果)
+type CryptoCaster struct{}
+
+func (CryptoCaster) Intn(n int) (int, error) {
+	if n <= 0 {
+		return 0, fmt.Errorf("Intn 的参数必须为正数, 实际为%d", n)
+	}
+	v, err := crand.Int(crand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
+
+func (CryptoCaster) Source() string {
+	return "crypto/rand"
+}
+
+// ReaderCaster 从文件或标准输入读取熵的随机源
+// 每次取一个字节, 以拒绝采样保证均匀分布。
+type ReaderCaster struct {
+	r      io.Reader
+	name   string
+	offset int64 // 起始偏移
+	read   int64 // 已读取字节数
+}
+
+// NewReaderCaster 以任意 io.Reader 为熵源, name 用于记录来源 (如 "stdin")
+// 读过的字节不会保留, 所以此类熵源起的卦无法经 NewCasterFromSource 重建。
+func NewReaderCaster(r io.Reader, name string) *ReaderCaster {
+	return &ReaderCaster{r: r, name: name}
+}
+
+// NewFileCaster 以文件 path 自 offset 字节起的内容为熵源
+func NewFileCaster(path string, offset int64) (*ReaderCaster, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &ReaderCaster{r: f, name: "file:" + path, offset: offset}, nil
+}
+
+func (c *ReaderCaster) Intn(n int) (int, error) {
+	if n <= 0 || n > 256 {
+		return 0, fmt.Errorf("熵源仅支持 1-256 的取值范围, 实际为%d", n)
+	}
+
+	limit := 256 - 256%n
+	buf := make([]byte, 1)
+	for {
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return 0, fmt.Errorf("读取熵源 %s 失败: %v", c.name, err)
+		}
+		c.read++
+		if int(buf[0]) < limit {
+			return int(buf[0]) % n, nil
+		}
+	}
+}
+
+// Source 返回 "名称@起始偏移+已读字节数", 如 "file:entropy.bin@0+18"
+// 文件熵源重建时只读这些字节, 起卦过程可逐字节复现;
+// 标准输入等一次性读取的熵源不保存已读内容, 无法复现, 只能依据卦中的掷币结果复盘。
+func (c *ReaderCaster) Source() string {
+	return fmt.Sprintf("%s@%d+%d", c.name, c.offset, c.read)
+}
+
+// Close 关闭底层熵源 (如果可关闭)
+func (c *ReaderCaster) Close() error {
+	if closer, ok := c.r.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// NewCasterFromSource 由卦中记录的 Source 重建随机源, 用于复现一次起卦
+// 支持 "seed:<n>" 与 "file:<path>@<offset>[+<length>]"; 给出 length 时只读这么多字节。
+// crypto/rand 与标准输入无法重建。
+func NewCasterFromSource(source string) (Caster, error) {
+	switch {
+	case strings.HasPrefix(source, "seed:"):
+		seed, err := strconv.ParseInt(strings.TrimPrefix(source, "seed:"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的种子记录 %q", source)
+		}
+		return NewSeededCaster(seed), nil
+	case strings.HasPrefix(source, "file:"):
+		spec := strings.TrimPrefix(source, "file:")
+		at := strings.LastIndex(spec, "@")
+		if at == -1 {
+			return NewFileCaster(spec, 0)
+		}
+		pos, length := spec[at+1:], int64(-1)
+		if plus := strings.Index(pos, "+"); plus != -1 {
+			n, err := strconv.ParseInt(pos[plus+1:], 10, 64)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("无效的文件熵源记录 %q", source)
+			}
+			pos, length = pos[:plus], n
+		}
+		offset, err := strconv.ParseInt(pos, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的文件熵源记录 %q", source)
+		}
+		c, err := NewFileCaster(spec[:at], offset)
+		if err != nil || length < 0 {
+			return c, err
+		}
+		c.r = limitedFile{io.LimitReader(c.r, length), c.r.(io.Closer)}
+		return c, nil
+	}
+	return nil, fmt.Errorf("随机源 %q 无法重建", source)
+}
+
+// limitedFile 限定读取长度, 仍可关闭底层文件
+type limitedFile struct {
+	io.Reader
+	io.Closer
+}
+
+// CastCoins 以三枚铜钱掷六次起卦 (自初爻至上爻)
+// 每枚铜钱 1=字 0=背, 掷币结果与随机源记录在卦中。
+func CastCoins(c Caster) (Gua, error) {
+	tosses := make([]string, 6)
+	for i := range tosses {
+		toss := make([]byte, 3)
+		for j := range toss {
+			v, err := c.Intn(2)
+			if err != nil {
+				return Gua{}, err
+			}
+			toss[j] = byte('0' + v)
+		}
+		tosses[i] = string(toss)
+	}
+
+	gua := GenerateGua(tosses)
+	gua.Source = c.Source()
+	return gua, nil
+}
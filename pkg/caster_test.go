@@ -0,0 +1,123 @@
+package pkg
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCastCoins_SeededIsReproducible(t *testing.T) {
+	first, err := CastCoins(NewSeededCaster(42))
+	if err != nil {
+		t.Fatalf("CastCoins failed: %v", err)
+	}
+	if first.Source != "seed:42" {
+		t.Errorf("Source = %q, want seed:42", first.Source)
+	}
+
+	caster, err := NewCasterFromSource(first.Source)
+	if err != nil {
+		t.Fatalf("NewCasterFromSource failed: %v", err)
+	}
+	second, err := CastCoins(caster)
+	if err != nil {
+		t.Fatalf("CastCoins failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(first.Tosses, second.Tosses) {
+		t.Errorf("replayed tosses %v != original %v", second.Tosses, first.Tosses)
+	}
+}
+
+func TestCastCoins_Reader(t *testing.T) {
+	// 偶数字节 -> 0 (背), 奇数字节 -> 1 (字)
+	entropy := []byte{
+		1, 1, 1, // 111 老阳
+		0, 0, 0, // 000 老阴
+		1, 1, 0, // 110 少阴
+		1, 0, 0, // 100 少阳
+		3, 5, 7, // 111 老阳
+		2, 4, 6, // 000 老阴
+	}
+	gua, err := CastCoins(NewReaderCaster(bytes.NewReader(entropy), "test"))
+	if err != nil {
+		t.Fatalf("CastCoins failed: %v", err)
+	}
+
+	want := []int{9, 6, 8, 7, 9, 6}
+	if !reflect.DeepEqual(gua.Lines, want) {
+		t.Errorf("Lines = %v, want %v", gua.Lines, want)
+	}
+	if gua.Source != "test@0+18" {
+		t.Errorf("Source = %q, want test@0+18", gua.Source)
+	}
+
+	// 熵不足时报错
+	if _, err := CastCoins(NewReaderCaster(bytes.NewReader(entropy[:5]), "short")); err == nil {
+		t.Error("expected error for exhausted entropy")
+	}
+}
+
+func TestReaderCaster_RejectionSampling(t *testing.T) {
+	// Intn(3): 255 >= 255 (256 - 256%3) 被拒绝, 取下一个字节 4 -> 1
+	c := NewReaderCaster(bytes.NewReader([]byte{255, 4}), "test")
+	v, err := c.Intn(3)
+	if err != nil {
+		t.Fatalf("Intn failed: %v", err)
+	}
+	if v != 1 {
+		t.Errorf("Intn(3) = %d, want 1", v)
+	}
+}
+
+func TestNewCasterFromSource_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entropy.bin")
+	data := []byte{9, 9, 1, 1, 1, 0, 0, 0, 1, 1, 0, 1, 0, 0, 1, 1, 1, 0, 0, 0}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewFileCaster(path, 2)
+	if err != nil {
+		t.Fatalf("NewFileCaster failed: %v", err)
+	}
+	first, err := CastCoins(c)
+	c.Close()
+	if err != nil {
+		t.Fatalf("CastCoins failed: %v", err)
+	}
+
+	if want := "file:" + path + "@2+18"; first.Source != want {
+		t.Errorf("Source = %q, want %q", first.Source, want)
+	}
+
+	replay, err := NewCasterFromSource(first.Source)
+	if err != nil {
+		t.Fatalf("NewCasterFromSource(%q) failed: %v", first.Source, err)
+	}
+	second, err := CastCoins(replay)
+	replay.(*ReaderCaster).Close()
+	if err != nil {
+		t.Fatalf("CastCoins failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(first.Tosses, second.Tosses) {
+		t.Errorf("replayed tosses %v != original %v", second.Tosses, first.Tosses)
+	}
+
+	// 只重放所记录的字节数, 多读即报错
+	short, err := NewCasterFromSource("file:" + path + "@2+17")
+	if err != nil {
+		t.Fatalf("NewCasterFromSource failed: %v", err)
+	}
+	if _, err := CastCoins(short); err == nil {
+		t.Error("expected error reading past the recorded length")
+	}
+	short.(*ReaderCaster).Close()
+
+	if _, err := NewCasterFromSource("crypto/rand"); err == nil {
+		t.Error("expected error rebuilding crypto/rand source")
+	}
+}
@@ -0,0 +1,136 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// 古法专占之事
+const (
+	CategoryLawsuit    = "Lawsuit"    // 官司
+	CategoryTravel     = "Travel"     // 出行
+	CategoryLostItem   = "LostItem"   // 失物
+	CategoryWeather    = "Weather"    // 天气
+	CategoryProperty   = "Property"   // 房宅
+	CategoryPregnancy  = "Pregnancy"  // 生育
+	CategoryExam       = "Exam"       // 考试
+	CategoryInvestment = "Investment" // 股票/投资
+)
+
+// CategoryInfo 一类求测事项: 用神取法与专用断法
+type CategoryInfo struct {
+	ID       string                   // 标识, 如 "Career"
+	Name     string                   // 中文名, 如 "求官/工作"
+	YongShen string                   // 用神六亲, 或 YongShenShiYao、YongShenYingYao
+	Variants map[string]string        // 依性别或角色改取用神, 如婚姻 "Female" 取官鬼
+	Personal bool                     // 用神即事主本人, 代他人占时改取其人之六亲
+	Judge    func(c *Chart) []Finding // 专用断法, 输出专占要素, 可为 nil
+	Verdict  JudgeModule              // 专门的吉凶断法, 为 nil 时只论用神旺衰
+}
+
+// YongShenFor 依性别或角色取用神, 无对应变体时取默认用神
+func (ci CategoryInfo) YongShenFor(variant string) string {
+	if y, ok := ci.Variants[variant]; ok {
+		return y
+	}
+	return ci.YongShen
+}
+
+// categoryMu 保护 categoryRegistry, 登记与查找可并发进行
+var categoryMu sync.RWMutex
+
+// categoryRegistry 已登记的求测事项, 按登记次序
+var categoryRegistry = []CategoryInfo{
+	{ID: CategoryCareer, Name: "求官/工作", YongShen: "官鬼"},
+	{ID: CategoryWealth, Name: "求财", YongShen: "妻财", Verdict: wealthVerdict},
+	// 男测以妻财为妻, 女测以官鬼为夫
+	{ID: CategoryMarriage, Name: "婚姻", YongShen: "妻财", Variants: map[string]string{"Female": "官鬼"}, Personal: true, Verdict: marriageVerdict},
+	{ID: CategoryStudy, Name: "学业", YongShen: "父母"},
+	{ID: CategorySafety, Name: "平安", YongShen: "子孙", Personal: true},
+	{ID: CategoryHealth, Name: "健康", YongShen: YongShenShiYao, Personal: true, Judge: healthRule},
+	{ID: CategorySiblings, Name: "兄弟/朋友", YongShen: "兄弟"},
+	{ID: CategoryParents, Name: "父母/长辈", YongShen: "父母"},
+	{ID: CategoryChildren, Name: "子孙/晚辈", YongShen: "子孙"},
+	{ID: CategoryLawsuit, Name: "官司", YongShen: "官鬼", Judge: lawsuitRule},
+	{ID: CategoryTravel, Name: "出行", YongShen: YongShenShiYao, Personal: true, Judge: travelRule},
+	{ID: CategoryLostItem, Name: "失物", YongShen: "妻财", Judge: lostItemRule},
+	{ID: CategoryWeather, Name: "天气", YongShen: "父母", Judge: weatherRule},
+	{ID: CategoryProperty, Name: "房宅", YongShen: "父母", Judge: propertyRule},
+	{ID: CategoryPregnancy, Name: "生育", YongShen: "子孙", Judge: pregnancyRule},
+	{ID: CategoryExam, Name: "考试", YongShen: "父母", Verdict: examVerdict},
+	{ID: CategoryInvestment, Name: "股票/投资", YongShen: "妻财", Judge: investmentRule},
+}
+
+// Categories 返回全部已登记的求测事项
+func Categories() []CategoryInfo {
+	categoryMu.RLock()
+	defer categoryMu.RUnlock()
+	return append([]CategoryInfo(nil), categoryRegistry...)
+}
+
+// CategoryIDs 返回全部求测事项的标识
+func CategoryIDs() []string {
+	categoryMu.RLock()
+	defer categoryMu.RUnlock()
+	ids := make([]string, len(categoryRegistry))
+	for i, ci := range categoryRegistry {
+		ids[i] = ci.ID
+	}
+	return ids
+}
+
+// LookupCategory 按标识 (不区分大小写) 或中文名查找求测事项
+func LookupCategory(name string) (CategoryInfo, bool) {
+	categoryMu.RLock()
+	defer categoryMu.RUnlock()
+	for _, ci := range categoryRegistry {
+		if strings.EqualFold(ci.ID, name) || ci.Name == name {
+			return ci, true
+		}
+	}
+	return CategoryInfo{}, false
+}
+
+// RegisterCategory 登记求测事项; 标识已存在时替换之
+func RegisterCategory(ci CategoryInfo) error {
+	if ci.ID == "" {
+		return fmt.Errorf("求测事项缺少标识")
+	}
+	for _, y := range append([]string{ci.YongShen}, variantYongShens(ci)...) {
+		if _, err := ParseLiuQin(y); err != nil && shiYingMark(y) == "" {
+			return fmt.Errorf("求测事项 %s 的用神 %q 无效", ci.ID, y)
+		}
+	}
+	if ci.Name == "" {
+		ci.Name = ci.ID
+	}
+
+	categoryMu.Lock()
+	defer categoryMu.Unlock()
+	for i, existing := range categoryRegistry {
+		if existing.ID == ci.ID {
+			categoryRegistry[i] = ci
+			return nil
+		}
+	}
+	categoryRegistry = append(categoryRegistry, ci)
+	return nil
+}
+
+func variantYongShens(ci CategoryInfo) []string {
+	var ys []string
+	for _, y := range ci.Variants {
+		ys = append(ys, y)
+	}
+	return ys
+}
+
+// categoryRule 执行求测事项的专用断法
+func categoryRule(c *Chart) []Finding {
+	ci, ok := LookupCategory(c.Ctx.Category)
+	if !ok || ci.Judge == nil {
+		return nil
+	}
+	return ci.Judge(c)
+}
@@ -0,0 +1,307 @@
+package pkg
+
+import "fmt"
+
+// 各类专占的断法。每条断法只在所属事项下由 categoryRule 调用, 输出带吉凶倾向的要素, 不改旺衰。
+
+// shiYingIndex 世爻或应爻之位, 无则 -1
+func shiYingIndex(c *Chart, mark string) int {
+	for i, info := range c.GuaInfo {
+		if info.ShiYing == mark {
+			return i
+		}
+	}
+	return -1
+}
+
+// categoryAdder 专占断法的输出助手
+func categoryAdder(findings *[]Finding) func(subject string, lines []int, d Direction, format string, args ...interface{}) {
+	return func(subject string, lines []int, d Direction, format string, args ...interface{}) {
+		*findings = append(*findings, Finding{Kind: FindingCategoryRule, Subject: subject, Lines: lines, Direction: d, Text: fmt.Sprintf(format, args...)})
+	}
+}
+
+// shenLines 某神所在之爻, 不现时为 nil
+func shenLines(s ShenState) []int {
+	if s.Present() {
+		return []int{s.Index}
+	}
+	return nil
+}
+
+// wuXingOf 某爻五行
+func wuXingOf(c *Chart, i int) string {
+	return GetWuXingFromGanZhi(c.GuaInfo[i].Ganzhi)
+}
+
+// lawsuitRule 官司: 世为己 (代占以其人之爻为事主), 应为对方, 官鬼为官府, 子孙动则讼散
+func lawsuitRule(c *Chart) []Finding {
+	var findings []Finding
+	add := categoryAdder(&findings)
+
+	shi, ying := c.Result.SubjectIndex, shiYingIndex(c, "应")
+	if shi < 0 || ying < 0 || shi == ying {
+		return nil
+	}
+	shiState, shiScore := shenAt(c, "世爻", shi)
+	yingState, yingScore := shenAt(c, "应爻", ying)
+	switch {
+	case shiScore > yingScore:
+		add("世应", []int{shi, ying}, Favorable, "世爻%s, 应爻%s: 世旺应衰, 我胜彼负", shiState.Strength, yingState.Strength)
+	case shiScore < yingScore:
+		add("世应", []int{shi, ying}, Unfavorable, "世爻%s, 应爻%s: 应旺世衰, 彼强我弱", shiState.Strength, yingState.Strength)
+	default:
+		add("世应", []int{shi, ying}, Neutral, "世应旺衰相当, 宜于和解")
+	}
+	if IsKe(wuXingOf(c, ying), wuXingOf(c, shi)) {
+		add("世应", []int{shi, ying}, Unfavorable, "应克世, 对方势盛, 宜防其害")
+	} else if IsKe(wuXingOf(c, shi), wuXingOf(c, ying)) {
+		add("世应", []int{shi, ying}, Favorable, "世克应, 我能制彼")
+	}
+
+	gui := findShen(c, "官府", GuanGui)
+	if gui.Present() {
+		guiWuXing := GetWuXingFromGanZhi(gui.Ganzhi)
+		switch {
+		case gui.Index == shi:
+			add("官府", []int{shi}, Unfavorable, "官鬼持世, 官非缠身")
+		case IsSheng(guiWuXing, wuXingOf(c, shi)) || IsKe(guiWuXing, wuXingOf(c, ying)):
+			add("官府", shenLines(gui), Favorable, "官鬼%s 生世或克应, 官府向我", gui.Ganzhi)
+		case IsKe(guiWuXing, wuXingOf(c, shi)) || IsSheng(guiWuXing, wuXingOf(c, ying)):
+			add("官府", shenLines(gui), Unfavorable, "官鬼%s 克世或生应, 官府向彼", gui.Ganzhi)
+		}
+	}
+
+	if zi := findShen(c, "子孙", ZiSun); zi.Active() {
+		add("子孙", shenLines(zi), Favorable, "子孙发动克官, 官非可散, 宜和解")
+	}
+	return findings
+}
+
+// travelRule 出行: 世为行人 (代占以其人之爻为行人), 世动则行, 官鬼发动途中有阻, 子孙有气一路平安, 父母为舟车行李
+func travelRule(c *Chart) []Finding {
+	var findings []Finding
+	add := categoryAdder(&findings)
+
+	shi, who := c.Result.YongShenIndex, "世爻"
+	if c.Ctx.Relation != RelationSelf {
+		who = "用神"
+	}
+	shiState, _ := shenAt(c, who, shi)
+	switch {
+	case shiState.Empty:
+		add(who, []int{shi}, Unfavorable, "%s旬空, 行意不坚, 恐难成行", who)
+	case shiState.Moving:
+		add(who, []int{shi}, Favorable, "%s发动, 行期可定", who)
+	default:
+		add(who, []int{shi}, Neutral, "%s安静, 行期未定", who)
+	}
+
+	if gui := findShen(c, "官鬼", GuanGui); gui.Active() {
+		if IsKe(GetWuXingFromGanZhi(gui.Ganzhi), wuXingOf(c, shi)) {
+			add("官鬼", shenLines(gui), Unfavorable, "官鬼发动克%s, 途中有险, 慎行", who)
+		} else {
+			add("官鬼", shenLines(gui), Unfavorable, "官鬼发动, 途中多阻")
+		}
+	}
+	if zi := findShen(c, "子孙", ZiSun); zi.Present() && zi.Strength != "弱" && !zi.Empty && !zi.Broken {
+		add("子孙", shenLines(zi), Favorable, "子孙有气, 一路平安")
+	}
+	if fu := findShen(c, "父母", FuMu); fu.Empty || fu.Broken {
+		add("父母", shenLines(fu), Unfavorable, "父母空破, 舟车不便, 行李有失")
+	}
+	return findings
+}
+
+// 地支方位
+var zhiDirections = map[string]string{
+	"子": "正北", "丑": "东北", "寅": "东北", "卯": "正东", "辰": "东南", "巳": "东南",
+	"午": "正南", "未": "西南", "申": "西南", "酉": "正西", "戌": "西北", "亥": "西北",
+}
+
+// lostItemRule 失物: 妻财为物, 以财爻地支定方位, 内卦近外卦远, 兄弟动劫财, 官鬼动为贼
+func lostItemRule(c *Chart) []Finding {
+	var findings []Finding
+	add := categoryAdder(&findings)
+
+	idx := c.Result.YongShenIndex
+	ganzhi := c.YongShenGanzhi()
+	zhi := zhiOf(ganzhi)
+	distance := "内卦, 物在近处或家中"
+	if idx >= 3 {
+		distance = "外卦, 物在远处或户外"
+	}
+	add("方位", []int{idx}, Neutral, "失物方位: %s (%s), %s", zhiDirections[zhi], zhi, distance)
+
+	cai, _ := shenAt(c, "妻财", idx)
+	switch {
+	case c.IsFuShen:
+		add("妻财", []int{idx}, Neutral, "妻财伏于%s之下, 物被遮掩, 细寻可得", c.Result.YongShenYao.LiuQin)
+	case cai.Empty:
+		add("妻财", []int{idx}, Unfavorable, "妻财旬空, 暂难寻获, 出空可见")
+	case cai.Broken:
+		add("妻财", []int{idx}, Unfavorable, "妻财破, 物恐已损")
+	case cai.Moving:
+		add("妻财", []int{idx}, Unfavorable, "妻财发动, 物已移位, 难于原处寻得")
+	default:
+		add("妻财", []int{idx}, Favorable, "妻财安静, 物在原处, 可寻")
+	}
+
+	if xiong := findShen(c, "兄弟", XiongDi); xiong.Active() {
+		add("兄弟", shenLines(xiong), Unfavorable, "兄弟发动劫财, 恐被人拿去")
+	}
+	if gui := findShen(c, "官鬼", GuanGui); gui.Active() {
+		add("官鬼", shenLines(gui), Unfavorable, "官鬼发动, 恐为贼盗所窃")
+	}
+	return findings
+}
+
+// 天气诸象: 父母雨, 妻财晴, 兄弟风, 官鬼雷电阴霾, 子孙日月星明
+var weatherSigns = []struct {
+	LiuQin LiuQin
+	Sign   string
+}{
+	{FuMu, "雨雪"},
+	{QiCai, "晴"},
+	{XiongDi, "风"},
+	{GuanGui, "雷电阴霾"},
+	{ZiSun, "晴明"},
+}
+
+// weatherRule 天气: 以发动之六亲论天象; 皆静则以父母、妻财旺衰定阴晴
+func weatherRule(c *Chart) []Finding {
+	var findings []Finding
+	add := categoryAdder(&findings)
+
+	var fu, cai ShenState
+	active := false
+	for _, w := range weatherSigns {
+		s := findShen(c, w.LiuQin.String(), w.LiuQin)
+		switch w.LiuQin {
+		case FuMu:
+			fu = s
+		case QiCai:
+			cai = s
+		}
+		if s.Active() {
+			active = true
+			add(s.LiuQin, shenLines(s), Neutral, "%s发动, 主%s", s.LiuQin, w.Sign)
+		}
+	}
+	if active {
+		return findings
+	}
+
+	fuScore, caiScore := weatherScore(c, fu), weatherScore(c, cai)
+	switch {
+	case fuScore > caiScore:
+		add("父母", shenLines(fu), Neutral, "诸爻安静, 父母旺于妻财, 天阴有雨")
+	case caiScore > fuScore:
+		add("妻财", shenLines(cai), Neutral, "诸爻安静, 妻财旺于父母, 天晴")
+	default:
+		add("天气", nil, Neutral, "诸爻安静, 阴晴参半")
+	}
+	return findings
+}
+
+// weatherScore 某神旺衰总分, 不现或空破者计为最低
+func weatherScore(c *Chart, s ShenState) int {
+	if !s.Present() || s.Empty || s.Broken {
+		return -100
+	}
+	_, score := shenAt(c, s.Role, s.Index)
+	return score
+}
+
+// propertyRule 房宅: 父母为房屋, 妻财动克父母则屋有损, 兄弟动破财, 应生世则交易有情
+func propertyRule(c *Chart) []Finding {
+	var findings []Finding
+	add := categoryAdder(&findings)
+
+	idx := c.Result.YongShenIndex
+	fu, _ := shenAt(c, "父母", idx)
+	switch {
+	case c.IsFuShen:
+		add("父母", []int{idx}, Unfavorable, "父母伏藏, 房屋未定或手续不全")
+	case fu.Empty || fu.Broken:
+		add("父母", []int{idx}, Unfavorable, "父母空破, 房屋不实或有损")
+	case fu.Strength == "弱":
+		add("父母", []int{idx}, Unfavorable, "父母衰弱, 房屋陈旧")
+	default:
+		add("父母", []int{idx}, Favorable, "父母有气, 房屋坚固")
+	}
+
+	if cai := findShen(c, "妻财", QiCai); cai.Active() {
+		add("妻财", shenLines(cai), Unfavorable, "妻财发动克父母, 屋宇有损, 修缮耗财")
+	}
+	if xiong := findShen(c, "兄弟", XiongDi); xiong.Active() {
+		add("兄弟", shenLines(xiong), Unfavorable, "兄弟发动, 交易破财")
+	}
+	if shi, ying := c.Result.SubjectIndex, shiYingIndex(c, "应"); shi >= 0 && ying >= 0 && shi != ying && IsSheng(wuXingOf(c, ying), wuXingOf(c, shi)) {
+		add("世应", []int{shi, ying}, Favorable, "应生世, 交易对方有情")
+	}
+	return findings
+}
+
+// pregnancyRule 生育: 子孙为胎, 旺动胎安, 空破胎气不固, 父母动克子孙; 阳爻为男, 阴爻为女
+func pregnancyRule(c *Chart) []Finding {
+	var findings []Finding
+	add := categoryAdder(&findings)
+
+	idx := c.Result.YongShenIndex
+	if !c.IsFuShen {
+		zi, _ := shenAt(c, "子孙", idx)
+		switch {
+		case zi.Empty || zi.Broken:
+			add("子孙", []int{idx}, Unfavorable, "子孙空破, 胎气不固")
+		case zi.Strength != "弱":
+			add("子孙", []int{idx}, Favorable, "子孙有气, 胎安易产")
+		default:
+			add("子孙", []int{idx}, Unfavorable, "子孙衰弱, 宜善调养")
+		}
+		if len(c.Ctx.GuaHexagram) > idx && c.Ctx.GuaHexagram[idx] == '1' {
+			add("子孙", []int{idx}, Neutral, "子孙临阳爻, 主男")
+		} else {
+			add("子孙", []int{idx}, Neutral, "子孙临阴爻, 主女")
+		}
+	} else {
+		add("子孙", []int{idx}, Unfavorable, "子孙伏藏, 胎孕未成或难见")
+	}
+
+	if fu := findShen(c, "父母", FuMu); fu.Active() {
+		add("父母", shenLines(fu), Unfavorable, "父母发动克子孙, 胎有所伤, 宜慎")
+	}
+	return findings
+}
+
+// investmentRule 股票/投资: 妻财为利, 化进神涨, 化退神跌, 子孙动生财, 兄弟动劫财
+func investmentRule(c *Chart) []Finding {
+	var findings []Finding
+	add := categoryAdder(&findings)
+
+	idx := c.Result.YongShenIndex
+	if !c.IsFuShen {
+		cai, _ := shenAt(c, "妻财", idx)
+		if cai.Empty {
+			add("妻财", []int{idx}, Unfavorable, "妻财旬空, 利多虚浮")
+		}
+		if cai.Moving && len(c.BianInfo) > idx {
+			switch CheckJinTui(c.GuaInfo[idx].Ganzhi, c.BianInfo[idx].Ganzhi) {
+			case "Jin Shen":
+				add("妻财", []int{idx}, Favorable, "妻财化进神, 行情看涨")
+			case "Tui Shen":
+				add("妻财", []int{idx}, Unfavorable, "妻财化退神, 行情看跌")
+			}
+		}
+	} else {
+		add("妻财", []int{idx}, Unfavorable, "妻财伏藏, 获利难见")
+	}
+
+	if zi := findShen(c, "子孙", ZiSun); zi.Active() {
+		add("子孙", shenLines(zi), Favorable, "子孙发动生财, 可望获利")
+	}
+	if xiong := findShen(c, "兄弟", XiongDi); xiong.Active() {
+		add("兄弟", shenLines(xiong), Unfavorable, "兄弟发动劫财, 谨防亏损")
+	}
+	return findings
+}
@@ -0,0 +1,151 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLookupCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		variant  string
+		yongShen string
+	}{
+		{"Career", CategoryCareer, "", "官鬼"},
+		{"marriage", CategoryMarriage, "Male", "妻财"},
+		{"婚姻", CategoryMarriage, "Female", "官鬼"},
+		{"失物", CategoryLostItem, "", "妻财"},
+		{"weather", CategoryWeather, "", "父母"},
+		{"出行", CategoryTravel, "Male", YongShenShiYao},
+		{"Health", CategoryHealth, "Female", YongShenShiYao},
+	}
+	for _, tt := range tests {
+		ci, ok := LookupCategory(tt.name)
+		if !ok {
+			t.Errorf("LookupCategory(%q) not found", tt.name)
+			continue
+		}
+		if ci.ID != tt.id || ci.YongShenFor(tt.variant) != tt.yongShen {
+			t.Errorf("LookupCategory(%q) = %s/%s, want %s/%s", tt.name, ci.ID, ci.YongShenFor(tt.variant), tt.id, tt.yongShen)
+		}
+	}
+	if _, ok := LookupCategory("Fortune"); ok {
+		t.Error("expected unknown category")
+	}
+}
+
+func TestRegisterCategory(t *testing.T) {
+	saved := categoryRegistry
+	defer func() { categoryRegistry = saved }()
+	categoryRegistry = append([]CategoryInfo(nil), saved...)
+
+	if err := RegisterCategory(CategoryInfo{ID: "Bad", YongShen: "财"}); err == nil {
+		t.Error("expected error for invalid 用神")
+	}
+	if err := RegisterCategory(CategoryInfo{ID: "Pet", YongShen: "子孙", Variants: map[string]string{"Owner": "世"}}); err == nil {
+		t.Error("expected error for invalid variant 用神")
+	}
+	if err := RegisterCategory(CategoryInfo{YongShen: "子孙"}); err == nil {
+		t.Error("expected error for missing ID")
+	}
+
+	if err := RegisterCategory(CategoryInfo{ID: "Pet", Name: "宠物", YongShen: "子孙"}); err != nil {
+		t.Fatalf("RegisterCategory failed: %v", err)
+	}
+	if ci, ok := LookupCategory("宠物"); !ok || ci.ID != "Pet" {
+		t.Errorf("registered category not found: %+v", ci)
+	}
+	if ids := CategoryIDs(); ids[len(ids)-1] != "Pet" {
+		t.Errorf("CategoryIDs() = %v, want Pet last", ids)
+	}
+
+	// 同标识者替换
+	n := len(categoryRegistry)
+	if err := RegisterCategory(CategoryInfo{ID: CategoryStudy, Name: "学业", YongShen: "官鬼"}); err != nil {
+		t.Fatalf("RegisterCategory failed: %v", err)
+	}
+	if ci, _ := LookupCategory(CategoryStudy); ci.YongShen != "官鬼" || len(categoryRegistry) != n {
+		t.Errorf("expected Study replaced in place, got %+v (%d entries)", ci, len(categoryRegistry))
+	}
+}
+
+func TestRegisterCategory_Concurrent(t *testing.T) {
+	saved := categoryRegistry
+	defer func() { categoryRegistry = saved }()
+	categoryRegistry = append([]CategoryInfo(nil), saved...)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			RegisterCategory(CategoryInfo{ID: fmt.Sprintf("Pet%d", i), YongShen: "子孙"})
+		}(i)
+		go func() {
+			defer wg.Done()
+			LookupCategory(CategoryHealth)
+			CategoryIDs()
+		}()
+	}
+	wg.Wait()
+	if got := len(categoryRegistry); got != len(saved)+8 {
+		t.Errorf("registry has %d entries, want %d", got, len(saved)+8)
+	}
+}
+
+func TestAnalyze_LostItem(t *testing.T) {
+	// 乾为天占失物: 妻财二爻甲寅, 寅为东北, 在内卦; 静而临日
+	ctx := qianCareerContext()
+	ctx.Category = CategoryLostItem
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.YongShen != "妻财" || result.YongShenIndex != 1 {
+		t.Fatalf("用神 = %s at %d, want 妻财 at 1", result.YongShen, result.YongShenIndex)
+	}
+
+	fs := result.FindingsOf(FindingCategoryRule)
+	if len(fs) != 2 {
+		t.Fatalf("expected 2 专占 findings, got %v", fs)
+	}
+	if !strings.Contains(fs[0].Text, "东北") || !strings.Contains(fs[0].Text, "内卦") {
+		t.Errorf("方位 = %q, want 东北 内卦", fs[0].Text)
+	}
+	if fs[1].Subject != "妻财" || fs[1].Direction != Favorable {
+		t.Errorf("妻财 = %+v, want favorable", fs[1])
+	}
+}
+
+func TestAnalyze_Lawsuit(t *testing.T) {
+	// 乾为天占官司: 世上爻壬戌, 应三爻甲辰, 官鬼四爻壬午生世
+	ctx := qianCareerContext()
+	ctx.Category = CategoryLawsuit
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	var guanFu *Finding
+	for _, f := range result.FindingsOf(FindingCategoryRule) {
+		if f.Subject == "官府" {
+			f := f
+			guanFu = &f
+		}
+	}
+	if guanFu == nil || guanFu.Direction != Favorable {
+		t.Errorf("expected 官府向我, got %v", guanFu)
+	}
+}
+
+func TestAnalyze_CategoryRuleOnlyForOwnCategory(t *testing.T) {
+	result, err := Analyze(qianCareerContext())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if fs := result.FindingsOf(FindingCategoryRule); len(fs) != 0 {
+		t.Errorf("Career has no 专占 rule, got %v", fs)
+	}
+}
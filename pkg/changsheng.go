@@ -1,5 +1,10 @@
 package pkg
 
+import (
+	"fmt"
+	"strings"
+)
+
 // 地支顺序：子丑寅卯辰巳午未申酉戌亥
 var dizhi = []string{
 	"子", "丑", "寅", "卯", "辰", "巳",
@@ -14,12 +19,74 @@ var changShengOrder = []string{
 }
 
 // 五行起长生表（核心，让长生计算正确！）
-var wuXingChangShengStart = map[string]string{
-	"木": "亥", // 木长生在亥
-	"火": "寅", // 火长生在寅
-	"土": "申", // 土长生在申（古法有申/巳两派，此处选申）
-	"金": "巳", // 金长生在巳
-	"水": "申", // 水长生在申（古法有申/酉两派，此处选申）
+var wuXingChangShengStart = map[WuXing]Zhi{
+	Mu:   ZhiHai,  // 木长生在亥
+	Huo:  ZhiYin,  // 火长生在寅
+	Tu:   ZhiShen, // 土长生依流派而定, 见 ChangShengSchool
+	Jin:  ZhiSi,   // 金长生在巳
+	Shui: ZhiShen, // 水长生在申
+}
+
+// ChangShengSchool 土之长生取法
+// 古法有两派: 水土同宫, 土随水长生在申; 火土同宫, 土寄巳长生。
+type ChangShengSchool int
+
+const (
+	ShuiTuTongGong ChangShengSchool = iota // 水土同宫, 土长生在申 (默认)
+	HuoTuTongGong                          // 火土同宫, 土长生在巳
+)
+
+var changShengSchoolNames = []string{"水土同宫", "火土同宫"}
+
+func (s ChangShengSchool) String() string {
+	if s < 0 || int(s) >= len(changShengSchoolNames) {
+		return ""
+	}
+	return changShengSchoolNames[s]
+}
+
+// TuStart 此派土之长生地支
+func (s ChangShengSchool) TuStart() Zhi {
+	if s == HuoTuTongGong {
+		return ZhiSi
+	}
+	return ZhiShen
+}
+
+// ParseChangShengSchool 解析土长生取法, 可用派名、长生地支或拼音, 如 "水土同宫"、"申"、"shuitu"
+func ParseChangShengSchool(s string) (ChangShengSchool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "水土同宫", "申", "shuitu", "shen":
+		return ShuiTuTongGong, nil
+	case "火土同宫", "巳", "huotu", "si":
+		return HuoTuTongGong, nil
+	}
+	return 0, fmt.Errorf("无效的土长生取法 %q, 应为 申 (水土同宫) 或 巳 (火土同宫)", s)
+}
+
+// Stage 计算地支 z 在此派五行 w 起长生体系下的长生位置
+func (s ChangShengSchool) Stage(w WuXing, z Zhi) string {
+	start, ok := wuXingChangShengStart[w]
+	if !ok || !z.Valid() {
+		return ""
+	}
+	if w == Tu {
+		start = s.TuStart()
+	}
+	// 计算相对距离
+	return changShengOrder[(z-start+12)%12]
+}
+
+// Mu 此派五行 w 之墓库, 如 火墓在戌
+func (s ChangShengSchool) Mu(w WuXing) Zhi {
+	start, ok := wuXingChangShengStart[w]
+	if !ok {
+		return -1
+	}
+	if w == Tu {
+		start = s.TuStart()
+	}
+	return (start + 8) % 12
 }
 
 func indexOf(slice []string, val string) int {
@@ -31,28 +98,223 @@ func indexOf(slice []string, val string) int {
 	return -1
 }
 
+// ChangSheng 以默认的水土同宫计算地支 z 在五行 w 起长生体系下的长生位置, 如 火 见 午 为 "帝旺"
+func ChangSheng(w WuXing, z Zhi) string {
+	return ShuiTuTongGong.Stage(w, z)
+}
+
 // GetChangSheng 计算某地支在某五行起长生体系下的长生位置
 // wuxing: 木火土金水
 // zhi: 要判断的地支，如 "午"
-// 返回值: 长生十二神之一，如 "帝旺"
+// 返回值: 长生十二神之一，如 "帝旺"; 输入无效时返回 ""
+// 土以水土同宫论, 等同 GetChangShengFor(ShuiTuTongGong, wuxing, zhi)。
 func GetChangSheng(wuxing, zhi string) string {
-	// 找五行的起长生点地支
-	startZhi, ok := wuXingChangShengStart[wuxing]
-	if !ok {
+	return GetChangShengFor(ShuiTuTongGong, wuxing, zhi)
+}
+
+// GetChangShengFor 按土长生取法 school 计算某地支在某五行起长生体系下的长生位置
+// 如 GetChangShengFor(HuoTuTongGong, "土", "酉") 为 "帝旺"; 输入无效时返回 ""
+func GetChangShengFor(school ChangShengSchool, wuxing, zhi string) string {
+	w, err := ParseWuXing(wuxing)
+	if err != nil {
+		return ""
+	}
+	z, err := ParseZhi(zhi)
+	if err != nil {
 		return ""
 	}
+	return school.Stage(w, z)
+}
 
-	// 找起点索引
-	startIdx := indexOf(dizhi, startZhi)
-	// 找目标地支索引
-	targetIdx := indexOf(dizhi, zhi)
+// dayStageScore 日上长生、帝旺、墓、绝之分值, 其余诸位不计
+func (p *ScoringProfile) dayStageScore(stage string) int {
+	switch stage {
+	case "长生":
+		return p.RiChangSheng
+	case "帝旺":
+		return p.RiDiWang
+	case "墓":
+		return p.RiMu
+	case "绝":
+		return p.RiJue
+	}
+	return 0
+}
 
-	if startIdx == -1 || targetIdx == -1 {
-		return ""
+// huaStageScore 动而化长生、帝旺、死、墓、绝之分值, 其余诸位不计
+func (p *ScoringProfile) huaStageScore(stage string) int {
+	switch stage {
+	case "长生":
+		return p.HuaChangSheng
+	case "帝旺":
+		return p.HuaDiWang
+	case "死":
+		return p.HuaSi
+	case "墓":
+		return p.HuaMu
+	case "绝":
+		return p.HuaJue
 	}
+	return 0
+}
 
-	// 计算相对距离
-	diff := (targetIdx - startIdx + 12) % 12
+// stageFindings 论一爻在日辰、动爻、变爻上的十二长生
+// bian 为该爻所化之爻, 静爻为 nil; 伏神以静爻论。
+func stageFindings(c *Chart, index int, info GuaInfo, bian *GuaInfo, subject string) []Finding {
+	var findings []Finding
+	add := func(kind FindingKind, score int, text string) {
+		findings = append(findings, Finding{Kind: kind, Subject: subject, Lines: []int{index},
+			Direction: directionOf(score), Score: score, Text: fmt.Sprintf("%s %s", info.Ganzhi, text)})
+	}
+
+	w, err := ParseWuXing(GetWuXingFromGanZhi(info.Ganzhi))
+	if err != nil {
+		return nil
+	}
+	z, err := ZhiFromGanZhi(info.Ganzhi)
+	if err != nil {
+		return nil
+	}
+	dayZhi, err := ParseZhi(c.Ctx.DayZhi)
+	if err != nil {
+		return nil
+	}
+
+	school, p := c.Ctx.ChangShengSchool, c.Profile
+	stageOf := func(other Zhi) string {
+		return GetChangShengFor(school, w.String(), other.String())
+	}
+	ruMu := false
+
+	// 日辰: 临日者不论墓绝
+	if dayZhi != z {
+		switch stage := stageOf(dayZhi); stage {
+		case "墓":
+			ruMu = true
+			add(FindingRuMu, p.dayStageScore(stage), fmt.Sprintf("入日墓(%s)", dayZhi))
+		case "绝":
+			if src := jueShengSource(c, index, w, bian); src != "" {
+				add(FindingJueChuSheng, p.JueChuSheng, fmt.Sprintf("临日绝(%s)而得%s生, 绝处逢生", dayZhi, src))
+			} else {
+				add(FindingJue, p.dayStageScore(stage), fmt.Sprintf("临日绝(%s)", dayZhi))
+			}
+		case "长生", "帝旺":
+			add(FindingChangSheng, p.dayStageScore(stage), fmt.Sprintf("日上%s(%s)", stage, dayZhi))
+		}
+	}
+
+	// 动墓: 他爻发动而为此爻之墓
+	for j, other := range c.GuaInfo {
+		if j == index || !c.IsMoving(j) {
+			continue
+		}
+		if oz, err := ZhiFromGanZhi(other.Ganzhi); err == nil && oz != z && stageOf(oz) == "墓" {
+			ruMu = true
+			add(FindingRuMu, p.DongMu, fmt.Sprintf("入动墓(%s %s)", other.Position, other.Ganzhi))
+			break
+		}
+	}
+
+	// 动而所化
+	huaMu := false
+	if bian != nil {
+		if bz, err := ZhiFromGanZhi(bian.Ganzhi); err == nil {
+			switch stage := stageOf(bz); stage {
+			case "墓":
+				huaMu = true
+				add(FindingRuMu, p.huaStageScore(stage), fmt.Sprintf("化墓(%s)", bian.Ganzhi))
+			case "绝":
+				if IsSheng(GetWuXingFromGanZhi(bian.Ganzhi), w.String()) {
+					add(FindingJueChuSheng, p.JueChuSheng, fmt.Sprintf("化绝(%s)而回头生, 绝处逢生", bian.Ganzhi))
+				} else {
+					add(FindingJue, p.huaStageScore(stage), fmt.Sprintf("化绝(%s)", bian.Ganzhi))
+				}
+			case "长生", "帝旺", "死":
+				add(FindingChangSheng, p.huaStageScore(stage), fmt.Sprintf("化%s(%s)", stage, bian.Ganzhi))
+			}
+		}
+	}
+
+	// 随鬼入墓: 世持官鬼而入墓, 或世爻化出官鬼墓库
+	if info.ShiYing == "世" {
+		guan := GuanGui.String()
+		if info.LiuQin == guan && (ruMu || huaMu) {
+			findings = append(findings, Finding{Kind: FindingSuiGuiRuMu, Subject: subject, Lines: []int{index},
+				Direction: Unfavorable, Score: p.SuiGuiRuMu, Text: fmt.Sprintf("%s 世持官鬼入墓, 随鬼入墓", info.Ganzhi)})
+		} else if huaMu && bian.LiuQin == guan {
+			findings = append(findings, Finding{Kind: FindingSuiGuiRuMu, Subject: subject, Lines: []int{index},
+				Direction: Unfavorable, Score: p.SuiGuiRuMu, Text: fmt.Sprintf("%s 世爻化官鬼入墓(%s), 随鬼入墓", info.Ganzhi, bian.Ganzhi)})
+		}
+	}
+	return findings
+}
+
+// jueShengSource 临日绝之爻是否得生: 日辰生之, 他爻发动生之, 或自化回头生
+// 返回生之者的描述, 无则返回 ""。
+func jueShengSource(c *Chart, index int, w WuXing, bian *GuaInfo) string {
+	if IsSheng(GetWuXing(c.Ctx.DayZhi), w.String()) {
+		return "日辰"
+	}
+	for j, other := range c.GuaInfo {
+		if j != index && c.IsMoving(j) && IsSheng(GetWuXingFromGanZhi(other.Ganzhi), w.String()) {
+			return other.Position
+		}
+	}
+	if bian != nil && IsSheng(GetWuXingFromGanZhi(bian.Ganzhi), w.String()) {
+		return "回头"
+	}
+	return ""
+}
 
-	return changShengOrder[diff]
+// changShengRule 以十二长生论各爻: 日墓、动墓、化墓, 日绝、化绝, 化死, 长生帝旺, 随鬼入墓, 绝处逢生
+// 用神 (伏藏时为伏神) 所逢者计分, 合计达 ±2 时旺衰升降一等。
+func changShengRule(c *Chart) []Finding {
+	result := c.Result
+	var findings []Finding
+
+	yongScore := 0
+	for i := len(c.GuaInfo) - 1; i >= 0; i-- {
+		info := c.GuaInfo[i]
+		var bian *GuaInfo
+		if c.IsMoving(i) && len(c.BianInfo) > i {
+			bian = &c.BianInfo[i]
+		}
+
+		isYongShen := i == result.YongShenIndex && !c.IsFuShen
+		subject := info.Position
+		if isYongShen {
+			subject = "用神"
+		}
+		for _, f := range stageFindings(c, i, info, bian, subject) {
+			if isYongShen {
+				yongScore += f.Score
+			} else if f.Kind != FindingSuiGuiRuMu {
+				// 非用神之爻仅作参考
+				f.Direction, f.Score = Neutral, 0
+			} else {
+				f.Score = 0
+			}
+			findings = append(findings, f)
+		}
+	}
+
+	if c.IsFuShen && c.FuShenGanzhi != "" {
+		fuShen := GuaInfo{Position: "伏神", Ganzhi: c.FuShenGanzhi}
+		for _, f := range stageFindings(c, result.YongShenIndex, fuShen, nil, "伏神") {
+			yongScore += f.Score
+			findings = append(findings, f)
+		}
+	}
+
+	switch {
+	case yongScore <= -2:
+		result.Strength = lowerStrength(result.Strength, "")
+		findings = append(findings, Finding{Kind: FindingChangSheng, Subject: "用神", Lines: []int{result.YongShenIndex},
+			Direction: Unfavorable, Text: "用神逢墓绝死地, 旺衰降一等"})
+	case yongScore >= 2:
+		result.Strength = raiseStrength(result.Strength, "")
+		findings = append(findings, Finding{Kind: FindingChangSheng, Subject: "用神", Lines: []int{result.YongShenIndex},
+			Direction: Favorable, Text: "用神得长生帝旺, 旺衰升一等"})
+	}
+	return findings
 }
@@ -50,3 +50,171 @@ func TestGetChangSheng(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyze_ChangSheng(t *testing.T) {
+	// 乾为天: 用神四爻壬午, 寅日火长生
+	ctx := qianCareerContext()
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if !result.HasFinding(FindingChangSheng, "用神") {
+		t.Errorf("expected 日上长生 on 用神, got %v", result.FindingsOf(FindingChangSheng))
+	}
+	if !result.HasFinding(FindingJue, "五爻") {
+		t.Error("expected 五爻壬申 临日绝 on 寅日")
+	}
+	for _, f := range result.FindingsOf(FindingJue) {
+		if f.Subject == "五爻" {
+			if got, want := f.Render(), "[绝] 五爻: 壬申 临日绝(寅)"; got != want {
+				t.Errorf("Render() = %q, want %q", got, want)
+			}
+		}
+	}
+
+	// 巳日: 三爻甲辰土临日绝, 而巳火生土, 绝处逢生
+	ctx.DayZhi = "巳"
+	result, err = Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if !result.HasFinding(FindingJueChuSheng, "三爻") {
+		t.Errorf("expected 绝处逢生 on 三爻, got %v", result.Findings)
+	}
+
+	// 午月戌日: 用神午火值月而入日墓, 旺衰降一等
+	ctx.DayZhi, ctx.MonthZhi = "戌", "午"
+	withoutRule := DefaultRules()
+	withoutRule.Disable(RuleChangSheng)
+	ctx.Rules = withoutRule
+	base, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	ctx.Rules = nil
+	result, err = Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if !result.HasFinding(FindingRuMu, "用神") {
+		t.Fatal("expected 用神入日墓")
+	}
+	if base.Strength != "强" || result.Strength != "中平" {
+		t.Errorf("Strength = %s -> %s, want 强 -> 中平", base.Strength, result.Strength)
+	}
+	// 不论日墓的方案: 分值为零, 旺衰不降
+	noMu := DefaultProfile
+	noMu.RiMu = 0
+	ctx.Profile = &noMu
+	result, err = Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	for _, f := range result.FindingsOf(FindingRuMu) {
+		if f.Subject == "用神" && f.Score != 0 {
+			t.Errorf("日墓 Score = %d, want 0 from profile", f.Score)
+		}
+	}
+	if result.Strength != "强" {
+		t.Errorf("Strength = %s, want 强 when 日墓 scores 0", result.Strength)
+	}
+}
+
+func TestAnalyze_SuiGuiRuMuAndHuaJue(t *testing.T) {
+	// 天山遁: 世在二爻丙午官鬼, 戌日入墓
+	ctx := qianCareerContext()
+	ctx.GuaHexagram, ctx.BianHexagram = "001111", "001111"
+	ctx.DayZhi = "戌"
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if !result.HasFinding(FindingSuiGuiRuMu, "用神") {
+		t.Errorf("expected 随鬼入墓, got %v", result.Findings)
+	}
+
+	// 二爻丙午发动化辛亥, 火绝在亥
+	ctx.BianHexagram = "011111"
+	ctx.Changed = []bool{false, true, false, false, false, false}
+	ctx.DayZhi = "寅"
+	result, err = Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if !result.HasFinding(FindingJue, "用神") {
+		t.Errorf("expected 化绝 on 用神, got %v", result.FindingsOf(FindingJue))
+	}
+}
+
+func TestChangShengSchool(t *testing.T) {
+	tests := []struct {
+		school ChangShengSchool
+		zhi    Zhi
+		want   string
+	}{
+		{ShuiTuTongGong, ZhiShen, "长生"},
+		{ShuiTuTongGong, ZhiChen, "墓"},
+		{HuoTuTongGong, ZhiSi, "长生"},
+		{HuoTuTongGong, ZhiYou, "帝旺"},
+		{HuoTuTongGong, ZhiChou, "墓"},
+	}
+	for _, tt := range tests {
+		if got := tt.school.Stage(Tu, tt.zhi); got != tt.want {
+			t.Errorf("%s: 土 见 %s = %s, want %s", tt.school, tt.zhi, got, tt.want)
+		}
+	}
+
+	for _, tt := range tests {
+		if got := GetChangShengFor(tt.school, "土", tt.zhi.String()); got != tt.want {
+			t.Errorf("GetChangShengFor(%s, 土, %s) = %s, want %s", tt.school, tt.zhi, got, tt.want)
+		}
+	}
+	if GetChangShengFor(HuoTuTongGong, "土", "酉") != "帝旺" || GetChangSheng("土", "酉") != "沐浴" {
+		t.Error("土见酉: 火土同宫为帝旺, 两参数者以水土同宫为沐浴")
+	}
+	if GetChangShengFor(HuoTuTongGong, "风", "午") != "" {
+		t.Error("expected empty for invalid 五行")
+	}
+
+	// 他行不受流派影响
+	if got := HuoTuTongGong.Stage(Huo, ZhiXu); got != "墓" {
+		t.Errorf("火 见 戌 = %s, want 墓", got)
+	}
+	if HuoTuTongGong.Mu(Tu) != ZhiChou || ShuiTuTongGong.Mu(Tu) != ZhiChen || HuoTuTongGong.Mu(Jin) != ZhiChou {
+		t.Error("unexpected 墓库")
+	}
+
+	for in, want := range map[string]ChangShengSchool{"": ShuiTuTongGong, "申": ShuiTuTongGong, "火土同宫": HuoTuTongGong, "HuoTu": HuoTuTongGong} {
+		if got, err := ParseChangShengSchool(in); err != nil || got != want {
+			t.Errorf("ParseChangShengSchool(%q) = %v, %v", in, got, err)
+		}
+	}
+	if _, err := ParseChangShengSchool("寅"); err == nil {
+		t.Error("expected error for 寅")
+	}
+}
+
+func TestAnalyze_ChangShengSchool(t *testing.T) {
+	// 乾为天三爻甲辰土, 丑日: 火土同宫者土墓在丑
+	ctx := qianCareerContext()
+	ctx.DayZhi = "丑"
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.HasFinding(FindingRuMu, "三爻") {
+		t.Error("水土同宫: 三爻 should not 入墓 on 丑日")
+	}
+
+	ctx.ChangShengSchool = HuoTuTongGong
+	result, err = Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if !result.HasFinding(FindingRuMu, "三爻") {
+		t.Error("火土同宫: expected 三爻 入日墓(丑)")
+	}
+	if result.ChangSheng != "火土同宫" {
+		t.Errorf("ChangSheng = %q", result.ChangSheng)
+	}
+}
@@ -0,0 +1,109 @@
+package pkg
+
+import "fmt"
+
+// FanFuYin 反吟/伏吟
+// 反吟主反复不定、去而复来; 伏吟主呻吟停滞、忧郁不伸。
+type FanFuYin struct {
+	Kind        string // "反吟" 或 "伏吟"
+	Level       string // "卦" (内外卦) 或 "爻"
+	Position    string // "内卦"/"外卦" 或爻位 (如 "三爻")
+	Lines       []int  // 涉及的爻 (0-5, 自初爻起)
+	Ben         string // 本卦之卦名或干支
+	Bian        string // 变卦之卦名或干支
+	Description string
+}
+
+// 卦反吟: 八卦方位相冲 (乾巽、坎离、艮坤、震兑)
+var trigramClash = map[string]string{
+	"乾": "巽", "巽": "乾",
+	"坎": "离", "离": "坎",
+	"艮": "坤", "坤": "艮",
+	"震": "兑", "兑": "震",
+}
+
+// 卦伏吟: 乾震纳甲地支相同, 变而不变
+var trigramFuYin = map[string]string{
+	"乾": "震", "震": "乾",
+}
+
+// DetectFanFuYin 检测本卦变卦之间的反吟、伏吟
+// 卦之反吟/伏吟看内外卦的变化; 爻之反吟/伏吟看动爻所化地支与本爻相冲或相同。
+func DetectFanFuYin(benHex, bianHex string, changed []bool) ([]FanFuYin, error) {
+	if len(benHex) != 6 || len(bianHex) != 6 {
+		return nil, fmt.Errorf("卦象长度必须为6位")
+	}
+	if benHex == bianHex {
+		return nil, nil
+	}
+
+	var findings []FanFuYin
+
+	// 1. 卦之反吟/伏吟
+	for _, half := range []struct {
+		name  string
+		start int
+	}{{"内卦", 0}, {"外卦", 3}} {
+		ben := trigramMap[benHex[half.start:half.start+3]]
+		bian := trigramMap[bianHex[half.start:half.start+3]]
+		if ben == bian {
+			continue
+		}
+		lines := []int{half.start, half.start + 1, half.start + 2}
+		switch bian {
+		case trigramClash[ben]:
+			findings = append(findings, FanFuYin{
+				Kind: "反吟", Level: "卦", Position: half.name, Lines: lines, Ben: ben, Bian: bian,
+				Description: fmt.Sprintf("%s%s化%s, 卦之反吟, 主事反复不定", half.name, ben, bian),
+			})
+		case trigramFuYin[ben]:
+			findings = append(findings, FanFuYin{
+				Kind: "伏吟", Level: "卦", Position: half.name, Lines: lines, Ben: ben, Bian: bian,
+				Description: fmt.Sprintf("%s%s化%s, 卦之伏吟, 主事停滞呻吟", half.name, ben, bian),
+			})
+		}
+	}
+
+	// 2. 爻之反吟/伏吟
+	benNaJia, err := ParseHexagram(benHex)
+	if err != nil {
+		return nil, err
+	}
+	bianNaJia, err := ParseHexagram(bianHex)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < 6 && i < len(changed); i++ {
+		if !changed[i] {
+			continue
+		}
+		benZhi := zhiOf(benNaJia[i])
+		bianZhi := zhiOf(bianNaJia[i])
+		switch {
+		case IsChong(benZhi, bianZhi):
+			findings = append(findings, FanFuYin{
+				Kind: "反吟", Level: "爻", Position: yaoPositions[i], Lines: []int{i},
+				Ben: benNaJia[i], Bian: bianNaJia[i],
+				Description: fmt.Sprintf("%s %s化%s, 爻之反吟", yaoPositions[i], benNaJia[i], bianNaJia[i]),
+			})
+		case benZhi == bianZhi:
+			findings = append(findings, FanFuYin{
+				Kind: "伏吟", Level: "爻", Position: yaoPositions[i], Lines: []int{i},
+				Ben: benNaJia[i], Bian: bianNaJia[i],
+				Description: fmt.Sprintf("%s %s化%s, 爻之伏吟", yaoPositions[i], benNaJia[i], bianNaJia[i]),
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+// involvesLine 判断反吟/伏吟是否涉及某爻
+func (f FanFuYin) involvesLine(index int) bool {
+	for _, l := range f.Lines {
+		if l == index {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package pkg
+
+import "testing"
+
+func TestDetectFanFuYin_Trigram(t *testing.T) {
+	// 乾为天 (111111) 内卦乾化巽 (011): 卦之反吟
+	findings, err := DetectFanFuYin("111111", "011111", []bool{true, false, false, false, false, false})
+	if err != nil {
+		t.Fatalf("DetectFanFuYin failed: %v", err)
+	}
+	if !hasFanFuYin(findings, "反吟", "卦", "内卦") {
+		t.Errorf("expected 内卦反吟, got %+v", findings)
+	}
+
+	// 乾为天 外卦乾化震 (100): 卦之伏吟, 动爻地支不变亦为爻之伏吟
+	findings, err = DetectFanFuYin("111111", "111100", []bool{false, false, false, false, true, true})
+	if err != nil {
+		t.Fatalf("DetectFanFuYin failed: %v", err)
+	}
+	if !hasFanFuYin(findings, "伏吟", "卦", "外卦") {
+		t.Errorf("expected 外卦伏吟, got %+v", findings)
+	}
+	if !hasFanFuYin(findings, "伏吟", "爻", "五爻") || !hasFanFuYin(findings, "伏吟", "爻", "上爻") {
+		t.Errorf("expected 五爻/上爻伏吟, got %+v", findings)
+	}
+}
+
+func TestDetectFanFuYin_Line(t *testing.T) {
+	// 坤为地 (000000) 二三爻动 化 地风升 (011000): 乙巳化辛亥, 乙卯化辛酉, 爻之反吟
+	findings, err := DetectFanFuYin("000000", "011000", []bool{false, true, true, false, false, false})
+	if err != nil {
+		t.Fatalf("DetectFanFuYin failed: %v", err)
+	}
+	if !hasFanFuYin(findings, "反吟", "爻", "二爻") || !hasFanFuYin(findings, "反吟", "爻", "三爻") {
+		t.Errorf("expected 二爻/三爻反吟, got %+v", findings)
+	}
+	// 坤化巽不属卦之反吟
+	for _, f := range findings {
+		if f.Level == "卦" {
+			t.Errorf("unexpected trigram finding %+v", f)
+		}
+	}
+
+	// 无动爻
+	if findings, _ := DetectFanFuYin("000000", "000000", make([]bool, 6)); len(findings) != 0 {
+		t.Errorf("expected no findings without moving lines, got %+v", findings)
+	}
+}
+
+func hasFanFuYin(findings []FanFuYin, kind, level, position string) bool {
+	for _, f := range findings {
+		if f.Kind == kind && f.Level == level && f.Position == position {
+			return true
+		}
+	}
+	return false
+}
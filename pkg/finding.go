@@ -0,0 +1,158 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FindingKind 断卦要素的种类
+type FindingKind string
+
+const (
+	FindingCategory     FindingKind = "事项"  // 求测事项与用神
+	FindingYongShen     FindingKind = "取用神" // 用神的选取
+	FindingFuShen       FindingKind = "伏神"  // 飞伏关系与伏神旺衰
+	FindingMonth        FindingKind = "月建"  // 月建对用神的旺衰
+	FindingDay          FindingKind = "日辰"  // 日辰对用神的旺衰
+	FindingBian         FindingKind = "变爻"  // 动爻所化
+	FindingHuiTouSheng  FindingKind = "回头生"
+	FindingHuiTouKe     FindingKind = "回头克"
+	FindingXieQi        FindingKind = "化泄"
+	FindingJinShen      FindingKind = "进神"
+	FindingTuiShen      FindingKind = "退神"
+	FindingYuePo        FindingKind = "月破"
+	FindingYueHe        FindingKind = "月合"
+	FindingRiHe         FindingKind = "日合"
+	FindingRiHai        FindingKind = "日害"
+	FindingRiXing       FindingKind = "日刑"
+	FindingXunKong      FindingKind = "旬空" // 真空或假空
+	FindingDongBuKong   FindingKind = "动不为空"
+	FindingChongKong    FindingKind = "冲空则实"
+	FindingYuePoHe      FindingKind = "月破逢合"
+	FindingAnDong       FindingKind = "暗动"
+	FindingRiPo         FindingKind = "日破"
+	FindingRiChong      FindingKind = "日冲"
+	FindingLine         FindingKind = "爻象" // 各爻详细分析
+	FindingMoving       FindingKind = "动爻" // 动爻互动及对用神的生克
+	FindingSanHe        FindingKind = "三合局"
+	FindingSanHui       FindingKind = "三会局"
+	FindingBranchBoost  FindingKind = "地支增强" // 三合三会未成实局
+	FindingBanHe        FindingKind = "半合"   // 生旺或旺墓两支
+	FindingGongHe       FindingKind = "拱合"   // 生墓两支拱旺地, 日月居其一
+	FindingFanYin       FindingKind = "反吟"
+	FindingFuYin        FindingKind = "伏吟"
+	FindingSanXing      FindingKind = XingSan
+	FindingXiangXing    FindingKind = XingXiang
+	FindingZiXing       FindingKind = XingZi
+	FindingRuMu         FindingKind = "入墓"   // 日墓、动墓、化墓
+	FindingJue          FindingKind = "绝"    // 日绝、化绝
+	FindingChangSheng   FindingKind = "十二长生" // 日上长生帝旺, 化长生、帝旺、死
+	FindingSuiGuiRuMu   FindingKind = "随鬼入墓"
+	FindingJueChuSheng  FindingKind = "绝处逢生"
+	FindingYuanShen     FindingKind = RoleYuanShen
+	FindingJiShen       FindingKind = RoleJiShen
+	FindingChouShen     FindingKind = RoleChouShen
+	FindingHealthSelf   FindingKind = RoleSelf
+	FindingIllness      FindingKind = RoleIllness
+	FindingMedicine     FindingKind = RoleMedicine
+	FindingDoctor       FindingKind = RoleDoctor
+	FindingCategoryRule FindingKind = "专占" // 求测事项的专用断法
+	FindingJudgment     FindingKind = "吉凶"
+)
+
+// Direction 要素对所测之事的倾向
+type Direction int
+
+const (
+	Neutral     Direction = 0
+	Favorable   Direction = 1
+	Unfavorable Direction = -1
+)
+
+func (d Direction) String() string {
+	switch {
+	case d > 0:
+		return "吉"
+	case d < 0:
+		return "凶"
+	}
+	return "平"
+}
+
+// directionOf 由分值推出倾向
+func directionOf(score int) Direction {
+	switch {
+	case score > 0:
+		return Favorable
+	case score < 0:
+		return Unfavorable
+	}
+	return Neutral
+}
+
+// Finding 一条断卦要素
+type Finding struct {
+	Kind      FindingKind
+	Subject   string    // 所论对象: "用神"、"伏神"、"月建"或爻位等
+	Lines     []int     // 涉及的爻 (0-5, 自初爻起); 日月等卦外要素不计
+	Direction Direction // 吉凶倾向
+	Score     int       // 对用神旺衰的分值增减
+	Text      string    // 补充说明, 由 Render 附于结构化字段之后
+}
+
+// Render 由结构化字段渲染中文描述, 如 "[月破] 用神 初爻 凶 -4: 用神月破"
+// 依次为种类、对象、所涉爻位、吉凶与分值, Text 作为说明附后; 字段为零值者及与对象相同的爻位略去
+func (f Finding) Render() string {
+	var sb strings.Builder
+	sb.WriteString("[" + string(f.Kind) + "]")
+	if f.Subject != "" {
+		sb.WriteString(" " + f.Subject)
+	}
+	if len(f.Lines) > 0 {
+		names := make([]string, 0, len(f.Lines))
+		for _, i := range f.Lines {
+			if i >= 0 && i < len(yaoPositions) {
+				names = append(names, yaoPositions[i])
+			}
+		}
+		// 对象即爻位者不再重出
+		if len(names) > 0 && !(len(names) == 1 && names[0] == f.Subject) {
+			sb.WriteString(" " + strings.Join(names, "、"))
+		}
+	}
+	if f.Direction != Neutral {
+		sb.WriteString(" " + f.Direction.String())
+	}
+	if f.Score != 0 {
+		sb.WriteString(fmt.Sprintf(" %+d", f.Score))
+	}
+	if f.Text != "" {
+		sb.WriteString(": " + f.Text)
+	}
+	return sb.String()
+}
+
+func (f Finding) String() string {
+	return f.Render()
+}
+
+// FindingsOf 返回某一种类的全部要素
+func (r AnalysisResult) FindingsOf(kind FindingKind) []Finding {
+	var out []Finding
+	for _, f := range r.Findings {
+		if f.Kind == kind {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// HasFinding 判断某对象是否带有某种要素, 如 HasFinding(FindingYuePo, "用神")
+func (r AnalysisResult) HasFinding(kind FindingKind, subject string) bool {
+	for _, f := range r.Findings {
+		if f.Kind == kind && f.Subject == subject {
+			return true
+		}
+	}
+	return false
+}
@@ -18,6 +18,9 @@ type Gua struct {
 	BenGua  []string
 	BianGua []string
 	Changed []bool
+	Lines   []int    // 原始爻值 (6=老阴 7=少阳 8=少阴 9=老阳), 自初爻至上爻
+	Tosses  []string // 原始掷币结果 (掷币起卦时)
+	Source  string   // 随机源记录 (如 "seed:42"), 用于复现
 }
 
 // 卦象信息
@@ -148,6 +151,7 @@ func GenerateGua(inputs []string) Gua {
 	benYao := make([]string, 6)
 	changed := make([]bool, 6)
 	binYao := make([]string, 6)
+	lines := make([]int, 6)
 
 	for i, input := range inputs {
 		count := strings.Count(input, "1")
@@ -157,16 +161,20 @@ func GenerateGua(inputs []string) Gua {
 			benYao[i] = "1"
 			changed[i] = true
 			binYao[i] = "0"
+			lines[i] = 9
 		case input == "000": // 老阴
 			benYao[i] = "0"
 			changed[i] = true
 			binYao[i] = "1"
+			lines[i] = 6
 		case count == 2: // 少阴 (两阳一阴) - 2 Heads + 1 Tail = 8 (Yin)
 			benYao[i] = "0"
 			binYao[i] = "0"
+			lines[i] = 8
 		case count == 1: // 少阳 (一阳两阴) - 1 Head + 2 Tails = 7 (Yang)
 			benYao[i] = "1"
 			binYao[i] = "1"
+			lines[i] = 7
 		}
 	}
 
@@ -174,10 +182,78 @@ func GenerateGua(inputs []string) Gua {
 		BenGua:  benYao,
 		Changed: changed,
 		BianGua: binYao,
+		Lines:   lines,
+		Tosses:  append([]string(nil), inputs...),
 	}
 
 }
 
+// GenerateGuaFromLines 由六个爻值 (6/7/8/9, 自初爻至上爻) 生成卦
+// 6=老阴(动) 7=少阳 8=少阴 9=老阳(动), 适用于手工掷币或蓍草起卦的记录
+func GenerateGuaFromLines(values []int) (Gua, error) {
+	if len(values) != 6 {
+		return Gua{}, fmt.Errorf("需要6个爻值, 实际为%d个", len(values))
+	}
+
+	benYao := make([]string, 6)
+	changed := make([]bool, 6)
+	binYao := make([]string, 6)
+	lines := make([]int, 6)
+
+	for i, v := range values {
+		switch v {
+		case 6: // 老阴
+			benYao[i], binYao[i] = "0", "1"
+			changed[i] = true
+		case 7: // 少阳
+			benYao[i], binYao[i] = "1", "1"
+		case 8: // 少阴
+			benYao[i], binYao[i] = "0", "0"
+		case 9: // 老阳
+			benYao[i], binYao[i] = "1", "0"
+			changed[i] = true
+		default:
+			return Gua{}, fmt.Errorf("第%d爻的爻值%d无效, 只允许6、7、8、9", i+1, v)
+		}
+		lines[i] = v
+	}
+
+	return Gua{
+		BenGua:  benYao,
+		Changed: changed,
+		BianGua: binYao,
+		Lines:   lines,
+	}, nil
+}
+
+// ParseLineValues 解析爻值字符串 (如 "789896", 自初爻至上爻) 并生成卦
+func ParseLineValues(s string) (Gua, error) {
+	values := make([]int, 0, 6)
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return Gua{}, fmt.Errorf("爻值字符串包含无效字符%q, 只允许6、7、8、9", r)
+		}
+		values = append(values, int(r-'0'))
+	}
+	return GenerateGuaFromLines(values)
+}
+
+// ParseLineValue 解析单个爻值, 返回爻符与名称 (与 ParseToss 的输出一致)
+func ParseLineValue(v int) (string, string) {
+	switch v {
+	case 9:
+		return "—○", "老阳"
+	case 6:
+		return "⚋×", "老阴"
+	case 8:
+		return "⚋ ", "少阴"
+	case 7:
+		return "⚊ ", "少阳"
+	default:
+		return "??", "未知"
+	}
+}
+
 // 解析投掷结果
 func ParseToss(toss string) (string, string) {
 	ones := 0
@@ -455,3 +531,81 @@ func GetFullGuaName(hex string) string {
 
 	return fmt.Sprintf("%s（%s%s%s）", baseName, palaceName, suffix, nature)
 }
+
+// GetHuGua 互卦: 以二三四爻为下卦, 三四五爻为上卦
+func GetHuGua(hex string) string {
+	if len(hex) != 6 {
+		return ""
+	}
+	return hex[1:4] + hex[2:5]
+}
+
+// GetCuoGua 错卦: 六爻阴阳皆反
+func GetCuoGua(hex string) string {
+	if len(hex) != 6 {
+		return ""
+	}
+	b := []byte(hex)
+	for i, c := range b {
+		if c == '1' {
+			b[i] = '0'
+		} else {
+			b[i] = '1'
+		}
+	}
+	return string(b)
+}
+
+// GetZongGua 综卦: 将卦上下颠倒
+func GetZongGua(hex string) string {
+	if len(hex) != 6 {
+		return ""
+	}
+	b := []byte(hex)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+
+// DerivedGua 由本卦 (或变卦) 推出的辅助卦
+type DerivedGua struct {
+	Kind        string // 互卦, 错卦, 综卦, 变卦互卦
+	Hexagram    string // 二进制 (自初爻至上爻)
+	Name        string // 卦名, 如 "雷泽归妹"
+	FullName    string // 含宫位的卦名, 如 "雷泽归妹（兑-归魂）"
+	GuaCi       string // 卦辞 (需先建立卦辞索引)
+	CoreMeaning string // 核心意象
+}
+
+func newDerivedGua(kind, hex string) DerivedGua {
+	d := DerivedGua{
+		Kind:     kind,
+		Hexagram: hex,
+		Name:     DetermineGuaName(hex),
+		FullName: GetFullGuaName(hex),
+	}
+	if text, _, err := QueryGuaAndYaoCi(d.Name, ""); err == nil {
+		d.GuaCi = text.GuaCi
+		d.CoreMeaning = text.CoreMeaning
+	}
+	return d
+}
+
+// GetDerivedGuas 返回本卦的互卦、错卦、综卦; 有变卦时再加变卦之互卦
+// bianHex 为空或与本卦相同 (无动爻) 时不计变卦互卦。
+func GetDerivedGuas(benHex, bianHex string) []DerivedGua {
+	if len(benHex) != 6 {
+		return nil
+	}
+
+	derived := []DerivedGua{
+		newDerivedGua("互卦", GetHuGua(benHex)),
+		newDerivedGua("错卦", GetCuoGua(benHex)),
+		newDerivedGua("综卦", GetZongGua(benHex)),
+	}
+	if len(bianHex) == 6 && bianHex != benHex {
+		derived = append(derived, newDerivedGua("变卦互卦", GetHuGua(bianHex)))
+	}
+	return derived
+}
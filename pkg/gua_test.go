@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLineValues(t *testing.T) {
+	// 7=少阳 8=少阴 9=老阳 8=少阴 9=老阳 6=老阴 (自初爻至上爻)
+	gua, err := ParseLineValues("789896")
+	if err != nil {
+		t.Fatalf("ParseLineValues failed: %v", err)
+	}
+
+	if got := strings.Join(gua.BenGua, ""); got != "101010" {
+		t.Errorf("BenGua = %s, want 101010", got)
+	}
+	if got := strings.Join(gua.BianGua, ""); got != "100001" {
+		t.Errorf("BianGua = %s, want 100001", got)
+	}
+
+	wantChanged := []bool{false, false, true, false, true, true}
+	for i, want := range wantChanged {
+		if gua.Changed[i] != want {
+			t.Errorf("Changed[%d] = %v, want %v", i, gua.Changed[i], want)
+		}
+	}
+
+	wantLines := []int{7, 8, 9, 8, 9, 6}
+	for i, want := range wantLines {
+		if gua.Lines[i] != want {
+			t.Errorf("Lines[%d] = %d, want %d", i, gua.Lines[i], want)
+		}
+	}
+}
+
+func TestParseLineValues_MatchesCoinToss(t *testing.T) {
+	// 111=老阳(9) 000=老阴(6) 110=少阴(8) 100=少阳(7)
+	coin := GenerateGua([]string{"111", "000", "110", "100", "110", "100"})
+	lines, err := ParseLineValues("968787")
+	if err != nil {
+		t.Fatalf("ParseLineValues failed: %v", err)
+	}
+
+	if strings.Join(coin.BenGua, "") != strings.Join(lines.BenGua, "") ||
+		strings.Join(coin.BianGua, "") != strings.Join(lines.BianGua, "") {
+		t.Errorf("coin %v/%v != lines %v/%v", coin.BenGua, coin.BianGua, lines.BenGua, lines.BianGua)
+	}
+	for i := range coin.Lines {
+		if coin.Lines[i] != lines.Lines[i] {
+			t.Errorf("Lines[%d]: coin %d != lines %d", i, coin.Lines[i], lines.Lines[i])
+		}
+	}
+}
+
+func TestParseLineValues_Invalid(t *testing.T) {
+	for _, input := range []string{"", "78989", "7898967", "789895", "78a896"} {
+		if _, err := ParseLineValues(input); err == nil {
+			t.Errorf("ParseLineValues(%q) expected error", input)
+		}
+	}
+}
+
+func TestHuCuoZongGua(t *testing.T) {
+	tests := []struct {
+		hex  string
+		hu   string
+		cuo  string
+		zong string
+	}{
+		// 地天泰: 互归妹, 错否, 综否
+		{"111000", "雷泽归妹", "天地否", "天地否"},
+		// 水雷屯: 互剥, 错鼎, 综蒙
+		{"100010", "山地剥", "火风鼎", "山水蒙"},
+		// 乾为天: 互错综皆为自身或坤
+		{"111111", "乾为天", "坤为地", "乾为天"},
+	}
+
+	for _, tt := range tests {
+		t.Run(DetermineGuaName(tt.hex), func(t *testing.T) {
+			if got := DetermineGuaName(GetHuGua(tt.hex)); got != tt.hu {
+				t.Errorf("互卦 = %s, want %s", got, tt.hu)
+			}
+			if got := DetermineGuaName(GetCuoGua(tt.hex)); got != tt.cuo {
+				t.Errorf("错卦 = %s, want %s", got, tt.cuo)
+			}
+			if got := DetermineGuaName(GetZongGua(tt.hex)); got != tt.zong {
+				t.Errorf("综卦 = %s, want %s", got, tt.zong)
+			}
+		})
+	}
+
+	if GetHuGua("111") != "" || GetCuoGua("") != "" || GetZongGua("1111111") != "" {
+		t.Error("expected empty result for invalid hexagram")
+	}
+}
+
+func TestGetDerivedGuas(t *testing.T) {
+	// 无动爻: 仅互错综
+	if got := GetDerivedGuas("111000", "111000"); len(got) != 3 {
+		t.Fatalf("expected 3 derived guas without moving lines, got %d", len(got))
+	}
+
+	// 地天泰 变 雷天大壮 (111100): 变卦互卦为 泽天夬 (111110)
+	derived := GetDerivedGuas("111000", "111100")
+	if len(derived) != 4 {
+		t.Fatalf("expected 4 derived guas, got %d", len(derived))
+	}
+	last := derived[3]
+	if last.Kind != "变卦互卦" || last.Name != "泽天夬" {
+		t.Errorf("变卦互卦 = %s %s, want 泽天夬", last.Kind, last.Name)
+	}
+	if last.FullName != GetFullGuaName("111110") {
+		t.Errorf("FullName = %s, want %s", last.FullName, GetFullGuaName("111110"))
+	}
+}
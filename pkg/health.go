@@ -0,0 +1,137 @@
+package pkg
+
+import "fmt"
+
+// 自占病诸神
+const (
+	RoleSelf     = "自身" // 世爻
+	RoleIllness  = "病症" // 官鬼
+	RoleMedicine = "医药" // 子孙
+	RoleDoctor   = "医者" // 父母: 医生、医院
+)
+
+// HealthReading 占病的诸神状态
+type HealthReading struct {
+	Self     ShenState // 病者, 即用神: 自占为世爻, 代占为其人之爻
+	Illness  ShenState // 官鬼
+	Medicine ShenState // 子孙
+	Doctor   ShenState // 父母
+}
+
+// keShi 某神是否克用神 (病者)
+func keShi(c *Chart, s ShenState) bool {
+	return s.Present() && s.Index != c.Result.YongShenIndex &&
+		IsKe(GetWuXingFromGanZhi(s.Ganzhi), GetWuXingFromGanZhi(c.GuaInfo[c.Result.YongShenIndex].Ganzhi))
+}
+
+// healthShen 取病、药、医之神。代占而其人之六亲与该神相同者 (如占子病, 子孙既为用神又为药),
+// 官鬼以忌神论病, 子孙以用神自身论药, 父母不另取医。
+func healthShen(c *Chart, patient LiuQin, role string, q LiuQin) ShenState {
+	if c.Ctx.Relation == RelationSelf || patient != q {
+		return findShen(c, role, q)
+	}
+	var s ShenState
+	switch q {
+	case GuanGui:
+		s = c.Result.JiShen
+	case ZiSun:
+		s, _ = shenAt(c, role, c.Result.YongShenIndex)
+	default:
+		s = ShenState{Index: -1}
+	}
+	s.Role = role
+	if s.LiuQin == "" {
+		s.LiuQin = q.String()
+	}
+	return s
+}
+
+// healthRule 占病: 用神为病者, 官鬼为病, 子孙为药, 父母为医
+// 自占以世爻为用神; 代占以其人之六亲为用神 (见 Relation)。
+func healthRule(c *Chart) []Finding {
+	ctx, result := c.Ctx, c.Result
+	if ctx.Category != CategoryHealth {
+		return nil
+	}
+	patient, err := yongShenLiuQin(result)
+	if err != nil {
+		return nil
+	}
+
+	var findings []Finding
+	add := func(s ShenState, kind FindingKind, d Direction, format string, args ...interface{}) {
+		f := Finding{Kind: kind, Subject: s.Role, Direction: d, Text: fmt.Sprintf(format, args...)}
+		if s.Present() {
+			f.Lines = []int{s.Index}
+		}
+		findings = append(findings, f)
+	}
+
+	selfRole, shi, body := RoleSelf, "世", "己身"
+	if ctx.Relation != RelationSelf {
+		selfRole, shi, body = ctx.Relation.Cn(), "用神", "其身"
+	}
+	self, _ := shenAt(c, selfRole, result.YongShenIndex)
+	self.Strength = result.Strength
+	h := &HealthReading{
+		Self:     self,
+		Illness:  healthShen(c, patient, RoleIllness, GuanGui),
+		Medicine: healthShen(c, patient, RoleMedicine, ZiSun),
+		Doctor:   healthShen(c, patient, RoleDoctor, FuMu),
+	}
+	result.Health = h
+	if ctx.Relation == RelationSelf {
+		add(h.Self, FindingHealthSelf, Neutral, "%s, 以世爻为用神", h.Self)
+	} else {
+		add(h.Self, FindingHealthSelf, Neutral, "%s, 代占%s之病, 以%s为用神", h.Self, ctx.Relation.Cn(), result.YongShen)
+	}
+
+	// 病症: 官鬼
+	gui := h.Illness
+	switch {
+	case !gui.Present():
+		add(gui, FindingIllness, Neutral, "%s, 病源难明", gui)
+	case gui.Index == result.YongShenIndex:
+		add(gui, FindingIllness, Unfavorable, "%s, %s持官鬼, 病在%s, 缠绵难去", gui, shi, body)
+	case gui.Empty || gui.Broken:
+		add(gui, FindingIllness, Favorable, "%s, 病轻易愈", gui)
+	case gui.Active() && keShi(c, gui):
+		add(gui, FindingIllness, Unfavorable, "%s, 动而克%s, 病势沉重", gui, shi)
+	case gui.Active():
+		add(gui, FindingIllness, Unfavorable, "%s, 官鬼发动, 病势方张", gui)
+	default:
+		add(gui, FindingIllness, Neutral, "%s, 官鬼安静, 病势平稳", gui)
+	}
+
+	// 医药: 子孙克官鬼
+	med := h.Medicine
+	switch {
+	case !med.Present():
+		add(med, FindingMedicine, Unfavorable, "%s, 难得对症之药", med)
+	case med.Empty || med.Broken:
+		add(med, FindingMedicine, Unfavorable, "%s, 子孙空破, 药不对症", med)
+	case med.Active():
+		add(med, FindingMedicine, Favorable, "%s, 子孙发动克鬼, 药能对症", med)
+	case med.Strength == "弱":
+		add(med, FindingMedicine, Neutral, "%s, 子孙衰弱, 药力不足", med)
+	default:
+		add(med, FindingMedicine, Favorable, "%s, 子孙有气, 服药渐效", med)
+	}
+
+	// 医者: 父母
+	doc := h.Doctor
+	switch {
+	case !doc.Present():
+		add(doc, FindingDoctor, Neutral, "%s, 宜另择医院", doc)
+	case doc.Empty || doc.Broken:
+		add(doc, FindingDoctor, Unfavorable, "%s, 父母空破, 难遇良医", doc)
+	case doc.Active() && med.Present():
+		// 父母克子孙, 医药相妨
+		add(doc, FindingDoctor, Unfavorable, "%s, 父母发动克子孙, 治疗受阻", doc)
+	case doc.Strength != "弱":
+		add(doc, FindingDoctor, Favorable, "%s, 父母有气, 宜求医就诊", doc)
+	default:
+		add(doc, FindingDoctor, Neutral, "%s, 父母衰弱, 医者力薄", doc)
+	}
+	return findings
+}
@@ -0,0 +1,57 @@
+package pkg
+
+import "testing"
+
+func TestAnalyze_Health(t *testing.T) {
+	// 乾为天自占病: 世在上爻壬戌, 官鬼四爻壬午逢子月月破
+	ctx := qianCareerContext()
+	ctx.Category = CategoryHealth
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.YongShen != YongShenShiYao || result.YongShenIndex != 5 {
+		t.Fatalf("用神 = %s at %d, want 世爻 at 5", result.YongShen, result.YongShenIndex)
+	}
+
+	h := result.Health
+	if h == nil {
+		t.Fatal("expected health reading")
+	}
+	tests := []struct {
+		name  string
+		state ShenState
+		index int
+	}{
+		{RoleSelf, h.Self, 5},
+		{RoleIllness, h.Illness, 3},
+		{RoleMedicine, h.Medicine, 0},
+		{RoleDoctor, h.Doctor, 2},
+	}
+	for _, tt := range tests {
+		if tt.state.Role != tt.name || tt.state.Index != tt.index {
+			t.Errorf("%s: got %s at %d, want index %d", tt.name, tt.state.Role, tt.state.Index, tt.index)
+		}
+	}
+	if !h.Illness.Broken {
+		t.Error("expected 官鬼 月破")
+	}
+
+	fs := result.FindingsOf(FindingIllness)
+	if len(fs) != 1 || fs[0].Direction != Favorable {
+		t.Errorf("expected 病轻易愈, got %v", fs)
+	}
+	if len(result.FindingsOf(FindingMedicine)) != 1 || len(result.FindingsOf(FindingDoctor)) != 1 {
+		t.Error("expected 医药 and 医者 findings")
+	}
+}
+
+func TestAnalyze_HealthOnlyForHealth(t *testing.T) {
+	result, err := Analyze(qianCareerContext())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.Health != nil || len(result.FindingsOf(FindingIllness)) != 0 {
+		t.Error("health reading should only apply to CategoryHealth")
+	}
+}
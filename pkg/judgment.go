@@ -0,0 +1,325 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Verdict 一次求测的吉凶结论, 及其依据与建议
+type Verdict struct {
+	Level   int       // 1 吉, 0 平, -1 凶
+	Reasons []Finding // 结论所据, Kind 皆为 FindingJudgment
+	Advice  []string  // 建议
+
+	net     int       // 专断诸条的吉凶合计
+	outlook [3]string // 依凶、平、吉给出的总体建议, 由断法设定
+}
+
+// Judgment 结论: 吉, 平, 凶
+func (v Verdict) Judgment() string {
+	switch {
+	case v.Level > 0:
+		return "吉"
+	case v.Level == 0:
+		return "平"
+	}
+	return "凶"
+}
+
+// weigh 记一条专断依据, 其吉凶计入合计
+func (v *Verdict) weigh(subject string, lines []int, d Direction, format string, args ...interface{}) {
+	v.net += int(d)
+	v.Reasons = append(v.Reasons, Finding{Kind: FindingJudgment, Subject: subject, Lines: lines, Direction: d, Text: fmt.Sprintf(format, args...)})
+}
+
+// advise 记一条建议
+func (v *Verdict) advise(format string, args ...interface{}) {
+	v.Advice = append(v.Advice, fmt.Sprintf(format, args...))
+}
+
+// JudgeModule 一类事项的专门吉凶断法: 在用神旺衰之上逐条记下依据与建议,
+// 诸条合计之吉凶使结论升降一等。
+type JudgeModule func(c *Chart, v *Verdict)
+
+// genericOutlook 无专门断法时的总体建议
+var genericOutlook = [3]string{
+	"卦象不佳，建议谨慎行事，静待时机。",
+	"吉凶参半，宜稳中求进，审时度势。",
+	"卦象吉利，可以积极行动，充满信心。",
+}
+
+// jiXiongLevel 以用神旺衰定吉凶等级, 再由原神、忌神、仇神升降一等
+func jiXiongLevel(yongShenStrength string, shens []ShenState) (int, []string) {
+	delta, notes := shenAdjustment(shens)
+	return clampLevel(strengthLevel(yongShenStrength) + delta), notes
+}
+
+// strengthLevel 旺衰的等级: 强 1, 中平 0, 弱 -1; 带注语者 (如 "弱 (合局克制)") 依其旺衰论
+func strengthLevel(strength string) int {
+	switch {
+	case strings.Contains(strength, "强"):
+		return 1
+	case strings.HasPrefix(strength, "中平"):
+		return 0
+	}
+	return -1
+}
+
+var strengthNames = []string{"弱", "中平", "强"}
+
+// raiseStrength 旺衰升一等, 已强者不变; note 为所附注语, 如 "飞神生助"
+func raiseStrength(strength, note string) string {
+	level := strengthLevel(strength)
+	if level >= 1 {
+		return strength
+	}
+	return withNote(strengthNames[level+2], note)
+}
+
+// lowerStrength 旺衰降一等, 已弱者不变
+func lowerStrength(strength, note string) string {
+	level := strengthLevel(strength)
+	if level <= -1 {
+		return strength
+	}
+	return withNote(strengthNames[level], note)
+}
+
+func withNote(strength, note string) string {
+	if note == "" {
+		return strength
+	}
+	return fmt.Sprintf("%s (%s)", strength, note)
+}
+
+func clampLevel(level int) int {
+	if level > 1 {
+		return 1
+	} else if level < -1 {
+		return -1
+	}
+	return level
+}
+
+// JudgeCategory 按求测事项断吉凶: 先以用神旺衰及原忌仇神定等级,
+// 再由事项的专门断法 (见 CategoryInfo.Verdict) 逐条论之, 合计升降一等, 并给出建议
+func JudgeCategory(c *Chart) Verdict {
+	ctx, result := c.Ctx, c.Result
+	level, notes := jiXiongLevel(result.Strength, []ShenState{result.YuanShen, result.JiShen, result.ChouShen})
+	v := Verdict{Level: level, outlook: genericOutlook}
+
+	ci, ok := LookupCategory(ctx.Category)
+	special := ok && ci.Verdict != nil
+	if special {
+		ci.Verdict(c, &v)
+		special = len(v.Reasons) > 0
+		switch {
+		case v.net > 0:
+			v.Level = clampLevel(v.Level + 1)
+		case v.net < 0:
+			v.Level = clampLevel(v.Level - 1)
+		}
+	}
+
+	// 用神旺衰之论随最终结论定吉凶倾向, 列于专断依据之前
+	d := Direction(v.Level)
+	base := make([]Finding, 0, len(notes)+1)
+	for _, n := range notes {
+		base = append(base, Finding{Kind: FindingJudgment, Subject: "用神", Lines: []int{result.YongShenIndex}, Direction: d, Text: n})
+	}
+	summary := fmt.Sprintf("吉凶判断: %s (基于用神旺衰: %s)", v.Judgment(), result.Strength)
+	if special {
+		summary = fmt.Sprintf("吉凶判断: %s (基于用神旺衰: %s, 合%s专断诸条)", v.Judgment(), result.Strength, ci.Name)
+	}
+	base = append(base, Finding{Kind: FindingJudgment, Subject: "用神", Lines: []int{result.YongShenIndex}, Direction: d, Text: summary})
+	v.Reasons = append(base, v.Reasons...)
+
+	v.Advice = append([]string{v.outlook[v.Level+1]}, v.Advice...)
+	return v
+}
+
+// marriageStar 婚姻中的财官之星: 用神之爻即是者取之, 否则于卦中寻之
+func marriageStar(c *Chart, role string, q LiuQin) ShenState {
+	if !c.IsFuShen && c.Result.YongShenYao.LiuQin == q.String() {
+		s, _ := shenAt(c, role, c.Result.YongShenIndex)
+		return s
+	}
+	return findShen(c, role, q)
+}
+
+// marriageStrengthNote 男女测婚时夫妻之星旺衰的断语
+// 以用神本身旺衰 (如 "强"、"弱 (合局克制)") 论, 不计原神忌神之升降。
+func marriageStrengthNote(gender, strength string) string {
+	level, _ := jiXiongLevel(strength, nil)
+	strong := level > 0
+	switch {
+	case gender == "Female" && strong:
+		return "女性测婚: 用神(官鬼)旺相，主夫星得力，缘分稳固。"
+	case gender == "Female":
+		return "女性测婚: 用神(官鬼)衰弱，需提防感情冷淡或阻碍。"
+	case strong:
+		return "男性测婚: 用神(妻财)旺相，主妻贤家富，感情和谐。"
+	}
+	return "男性测婚: 用神(妻财)衰弱，可能暗示求财或感情不顺。"
+}
+
+// marriageVerdict 婚姻: 世应生合定两家之情, 财官论夫妻之配, 兄弟发动为争夺之人
+// 代占他人婚姻 (子女等) 时, 以事主之爻代世, 不论男女之星。
+func marriageVerdict(c *Chart, v *Verdict) {
+	ctx, result := c.Ctx, c.Result
+	v.outlook = [3]string{
+		"婚事多阻, 不宜勉强, 宜待时机或另觅良缘。",
+		"婚事可成而多波折, 宜多沟通, 以诚相待。",
+		"缘分已到, 宜主动推进, 可于应期议定。",
+	}
+
+	self, ying := result.SubjectIndex, shiYingIndex(c, "应")
+	who := "世"
+	proxy := ctx.Relation != RelationSelf && ctx.Relation != RelationSpouse
+	if proxy {
+		who = ctx.Relation.Cn()
+		v.weigh("事主", []int{self}, Neutral, "代占%s婚姻, 以%s %s为事主, 应为对方", who, c.GuaInfo[self].Position, c.GuaInfo[self].LiuQin)
+	} else {
+		// 问配偶之婚姻即自身婚姻
+		self = shiYingIndex(c, "世")
+		v.weigh("用神", []int{result.YongShenIndex}, Neutral, "%s", marriageStrengthNote(ctx.Gender, result.Strength))
+	}
+
+	// 世应: 两家之情
+	if self >= 0 && ying >= 0 && self != ying {
+		lines := []int{self, ying}
+		selfWuXing, yingWuXing := wuXingOf(c, self), wuXingOf(c, ying)
+		switch selfZhi, yingZhi := zhiOf(c.GuaInfo[self].Ganzhi), zhiOf(c.GuaInfo[ying].Ganzhi); {
+		case CheckLiuHe(selfZhi, yingZhi) != "":
+			v.weigh("世应", lines, Favorable, "%s应相合, 两情相投", who)
+		case IsChong(selfZhi, yingZhi):
+			v.weigh("世应", lines, Unfavorable, "%s应相冲, 意见相左, 难以成合", who)
+			v.advise("双方意见相左, 宜先求同存异, 勿操之过急。")
+		case IsSheng(yingWuXing, selfWuXing):
+			v.weigh("世应", lines, Favorable, "应生%s, 对方有意", who)
+		case IsSheng(selfWuXing, yingWuXing):
+			v.weigh("世应", lines, Neutral, "%s生应, 此方主动, 对方意淡", who)
+		case IsKe(yingWuXing, selfWuXing):
+			v.weigh("世应", lines, Unfavorable, "应克%s, 对方强势, 恐生嫌隙", who)
+		case IsKe(selfWuXing, yingWuXing):
+			v.weigh("世应", lines, Unfavorable, "%s克应, 此方嫌弃, 难以和谐", who)
+		default:
+			v.weigh("世应", lines, Neutral, "%s应比和, 门户相当", who)
+		}
+	}
+
+	// 财官: 夫妻之配
+	if !proxy {
+		cai, gui := marriageStar(c, "妻财", QiCai), marriageStar(c, "官鬼", GuanGui)
+		lines := append(shenLines(cai), shenLines(gui)...)
+		switch {
+		case !cai.Present() || !gui.Present():
+			v.weigh("财官", lines, Unfavorable, "财官不全, 夫妻之星有缺")
+		case cai.Empty || cai.Broken || gui.Empty || gui.Broken:
+			v.weigh("财官", lines, Unfavorable, "财官空破, 一方心意不实")
+			v.advise("对方心意未定, 宜待出空填实之时再议。")
+		case CheckLiuHe(zhiOf(cai.Ganzhi), zhiOf(gui.Ganzhi)) != "":
+			v.weigh("财官", lines, Favorable, "财官相合, 夫妻和合")
+		case cai.Strength != "弱" && gui.Strength != "弱":
+			v.weigh("财官", lines, Favorable, "财官俱旺, 两相匹配")
+		default:
+			v.weigh("财官", lines, Neutral, "财官一旺一衰, 强弱悬殊")
+		}
+	}
+
+	// 兄弟发动为争夺之人; 女占子孙发动克官, 伤夫星
+	if xiong := findShen(c, "兄弟", XiongDi); xiong.Active() {
+		if ctx.Gender == "Female" || proxy {
+			v.weigh("兄弟", shenLines(xiong), Unfavorable, "兄弟发动, 同辈相争, 恐有第三者")
+		} else {
+			v.weigh("兄弟", shenLines(xiong), Unfavorable, "兄弟发动克妻财, 有人相争, 亦主破耗")
+		}
+		v.advise("提防第三者介入, 彼此坦诚相待。")
+	}
+	if zi := findShen(c, "子孙", ZiSun); !proxy && ctx.Gender == "Female" && zi.Active() {
+		v.weigh("子孙", shenLines(zi), Unfavorable, "子孙发动克官鬼, 夫星受伤")
+	}
+}
+
+// wealthVerdict 求财: 妻财为财, 子孙为财源, 兄弟为劫财之人
+func wealthVerdict(c *Chart, v *Verdict) {
+	v.outlook = [3]string{
+		"求财不利, 宜守不宜攻, 暂缓投资扩张。",
+		"小财可得, 宜量力而行, 见好就收。",
+		"财运亨通, 可积极求取, 应期得财。",
+	}
+
+	xiong, zi := findShen(c, "兄弟", XiongDi), findShen(c, "子孙", ZiSun)
+	switch {
+	case xiong.Active() && zi.Active():
+		v.weigh("兄弟", append(shenLines(xiong), shenLines(zi)...), Favorable, "兄弟与子孙同动, 兄生子、子生财, 劫而复生, 反主得财")
+	case xiong.Active():
+		v.weigh("兄弟", shenLines(xiong), Unfavorable, "兄弟发动劫财, 恐有争夺破耗")
+		v.advise("不宜合伙、借贷, 谨防小人争利。")
+	case zi.Active():
+		v.weigh("子孙", shenLines(zi), Favorable, "子孙发动生财, 财源不绝")
+		v.advise("财源得力, 可顺势开拓。")
+	case !zi.Present():
+		v.weigh("子孙", nil, Neutral, "子孙不现, 财源不显, 得之不易")
+	case zi.Empty || zi.Broken:
+		v.weigh("子孙", shenLines(zi), Unfavorable, "子孙空破, 财源不继")
+	}
+
+	if shi := c.Result.SubjectIndex; shi >= 0 {
+		switch c.GuaInfo[shi].LiuQin {
+		case XiongDi.String():
+			v.weigh("世爻", []int{shi}, Unfavorable, "兄弟持世, 财难入手")
+			v.advise("守成为上, 切忌冒进。")
+		case QiCai.String():
+			v.weigh("世爻", []int{shi}, Favorable, "妻财持世, 财来就我")
+		}
+	}
+}
+
+// examVerdict 考试: 父母为文章试卷, 官鬼为功名名次; 子孙动伤官, 妻财动坏文书
+func examVerdict(c *Chart, v *Verdict) {
+	v.outlook = [3]string{
+		"此番难中, 宜加倍用功, 以待来日。",
+		"成绩中等, 宜查漏补缺, 稳定发挥。",
+		"金榜可期, 保持状态即可。",
+	}
+
+	idx := c.Result.YongShenIndex
+	fu, _ := shenAt(c, "父母", idx)
+	switch {
+	case c.IsFuShen || fu.Empty || fu.Broken:
+		v.weigh("父母", []int{idx}, Unfavorable, "父母伏藏或空破, 文章不佳, 答卷有失")
+	case fu.Strength != "弱":
+		v.weigh("父母", []int{idx}, Favorable, "父母有气, 文章得力")
+	default:
+		v.weigh("父母", []int{idx}, Neutral, "父母衰弱, 文章平平")
+	}
+
+	shi := c.Result.SubjectIndex
+	gui := findShen(c, "官鬼", GuanGui)
+	switch {
+	case !gui.Present():
+		v.weigh("官鬼", nil, Unfavorable, "官鬼不现, 名次无凭")
+	case gui.Index == shi:
+		v.weigh("官鬼", []int{shi}, Favorable, "官鬼持世, 功名有望")
+	case gui.Empty || gui.Broken:
+		v.weigh("官鬼", shenLines(gui), Unfavorable, "官鬼空破, 功名难就")
+	case shi >= 0 && IsSheng(GetWuXingFromGanZhi(gui.Ganzhi), wuXingOf(c, shi)):
+		v.weigh("官鬼", shenLines(gui), Favorable, "官鬼生世, 榜上有名")
+	case gui.Active():
+		v.weigh("官鬼", shenLines(gui), Favorable, "官鬼发动生父母, 名次靠前")
+	case gui.Strength == "弱":
+		v.weigh("官鬼", shenLines(gui), Neutral, "官鬼衰弱, 名次平平")
+	default:
+		v.weigh("官鬼", shenLines(gui), Favorable, "官鬼有气, 名次可期")
+	}
+
+	if zi := findShen(c, "子孙", ZiSun); zi.Active() {
+		v.weigh("子孙", shenLines(zi), Unfavorable, "子孙发动伤官, 功名受阻")
+		v.advise("考前勿松懈, 谨防发挥失常。")
+	}
+	if cai := findShen(c, "妻财", QiCai); cai.Active() {
+		v.weigh("妻财", shenLines(cai), Unfavorable, "妻财发动克父母, 文章有损")
+		v.advise("审题务必细心, 谨防笔误漏答。")
+	}
+}
@@ -0,0 +1,132 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+// movingQian 乾为天, 令某爻发动
+func movingQian(category string, moving ...int) AnalysisContext {
+	ctx := qianCareerContext()
+	ctx.Category = category
+	bian := []byte(ctx.BianHexagram)
+	for _, i := range moving {
+		ctx.Changed[i] = true
+		bian[i] = '0'
+	}
+	ctx.BianHexagram = string(bian)
+	return ctx
+}
+
+// judgmentBy 某对象的专断依据
+func judgmentBy(result AnalysisResult, subject string) (Finding, bool) {
+	for _, f := range result.FindingsOf(FindingJudgment) {
+		if f.Subject == subject {
+			return f, true
+		}
+	}
+	return Finding{}, false
+}
+
+func TestJudgeCategory(t *testing.T) {
+	// 乾为天: 妻财二爻甲寅, 官鬼四爻壬午 (子月月破), 兄弟五爻壬申, 世上爻壬戌冲应三爻甲辰
+	tests := []struct {
+		name      string
+		ctx       AnalysisContext
+		judgment  string
+		subject   string    // 须有的专断依据
+		direction Direction // 该依据的吉凶
+		advice    string    // 建议中须含之语
+	}{
+		{"求财 安静", movingQian(CategoryWealth), "吉", "", Neutral, "财运亨通"},
+		{"求财 兄弟劫财", movingQian(CategoryWealth, 4), "凶", "兄弟", Unfavorable, "不宜合伙"},
+		{"婚姻 世应相冲", movingQian(CategoryMarriage), "平", "世应", Unfavorable, "求同存异"},
+		{"婚姻 财官空破", movingQian(CategoryMarriage), "平", "财官", Unfavorable, "出空填实"},
+		{"婚姻 兄弟争夺", movingQian(CategoryMarriage, 4), "凶", "兄弟", Unfavorable, "第三者"},
+		{"考试 父母空", movingQian(CategoryExam), "凶", "父母", Unfavorable, "加倍用功"},
+		{"考试 官鬼破", movingQian(CategoryExam), "凶", "官鬼", Unfavorable, ""},
+		{"求官 通论", movingQian(CategoryCareer), "凶", "", Neutral, "谨慎行事"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Analyze(tt.ctx)
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+			if result.Judgment != tt.judgment {
+				t.Errorf("Judgment = %s, want %s", result.Judgment, tt.judgment)
+			}
+			if tt.subject != "" {
+				f, ok := judgmentBy(result, tt.subject)
+				if !ok || f.Direction != tt.direction {
+					t.Errorf("%s = %+v, want direction %v", tt.subject, f, tt.direction)
+				}
+			}
+			if tt.advice != "" && !strings.Contains(strings.Join(result.Advice, " "), tt.advice) {
+				t.Errorf("Advice = %v, want %q", result.Advice, tt.advice)
+			}
+		})
+	}
+}
+
+func TestJudgeCategory_OnlyOwnModule(t *testing.T) {
+	result, err := Analyze(movingQian(CategoryCareer, 4))
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	for _, f := range result.FindingsOf(FindingJudgment) {
+		if f.Subject != "用神" {
+			t.Errorf("求官无专门断法, 不应有 %s: %s", f.Subject, f.Text)
+		}
+	}
+	if len(result.Advice) != 1 {
+		t.Errorf("Advice = %v, want one generic advice", result.Advice)
+	}
+}
+
+func TestJudgeCategory_MarriageFemale(t *testing.T) {
+	ctx := movingQian(CategoryMarriage, 4)
+	ctx.Gender = "Female"
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	f, ok := judgmentBy(result, "兄弟")
+	if !ok || !strings.Contains(f.Text, "第三者") {
+		t.Errorf("女占兄弟发动 = %+v, want 第三者", f)
+	}
+	var note bool
+	for _, f := range result.FindingsOf(FindingJudgment) {
+		note = note || strings.Contains(f.Text, "女性测婚")
+	}
+	if !note {
+		t.Error("expected 女性测婚 note")
+	}
+}
+
+func TestMarriageVerdict_StrengthNoteIgnoresJiShen(t *testing.T) {
+	// 坤为地女测婚: 官鬼三爻乙卯得子月生、临寅日为强; 上爻癸酉子孙发动为忌神
+	ctx := qianCareerContext()
+	ctx.GuaHexagram, ctx.BianHexagram = "000000", "000001"
+	ctx.Changed[5] = true
+	ctx.Category, ctx.Gender = CategoryMarriage, "Female"
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if !strings.Contains(result.Strength, "强") || !result.JiShen.Active() {
+		t.Fatalf("want 强 用神 with active 忌神, got %s / %s", result.Strength, result.JiShen)
+	}
+	var note string
+	for _, f := range result.FindingsOf(FindingJudgment) {
+		if strings.Contains(f.Text, "测婚") {
+			note = f.Text
+		}
+	}
+	if !strings.Contains(note, "夫星得力") {
+		t.Errorf("测婚 note = %q, want 旺相 note for 强 用神", note)
+	}
+	if _, details := JudgeJiXiong(result.Strength, CategoryMarriage, "Female"); !strings.Contains(strings.Join(details, ""), note) {
+		t.Errorf("JudgeJiXiong details %v disagree with Analyze note %q", details, note)
+	}
+}
@@ -0,0 +1,143 @@
+package pkg
+
+import "strings"
+
+// KongState 旬空之状态
+type KongState int
+
+const (
+	KongNone  KongState = iota // 不空
+	KongZhen                   // 真空: 休囚静空, 或空而又破, 到底为空
+	KongJia                    // 假空: 旺相或得日生扶, 出空即实
+	KongDong                   // 动不为空
+	KongChong                  // 冲空则实: 旺相之空逢日冲
+)
+
+var kongStateNames = []string{"", "真空", "假空", "动不为空", "冲空则实"}
+
+func (k KongState) String() string {
+	if k < 0 || int(k) >= len(kongStateNames) {
+		return ""
+	}
+	return kongStateNames[k]
+}
+
+// PoState 月破之状态
+type PoState int
+
+const (
+	PoNone  PoState = iota // 不破
+	PoYue                  // 月破
+	PoYueHe                // 月破逢日合, 破而得补
+)
+
+var poStateNames = []string{"", "月破", "月破逢合"}
+
+func (p PoState) String() string {
+	if p < 0 || int(p) >= len(poStateNames) {
+		return ""
+	}
+	return poStateNames[p]
+}
+
+// ChongState 逢日冲之状态
+type ChongState int
+
+const (
+	ChongNone    ChongState = iota // 日不冲
+	ChongAnDong                    // 暗动: 静爻旺相逢日冲
+	ChongRiPo                      // 日破: 静爻休囚逢日冲
+	ChongRiChong                   // 动爻逢日冲
+)
+
+var chongStateNames = []string{"", "暗动", "日破", "日冲"}
+
+func (c ChongState) String() string {
+	if c < 0 || int(c) >= len(chongStateNames) {
+		return ""
+	}
+	return chongStateNames[c]
+}
+
+// LineStatus 一爻在日月下的空、破、冲状态
+type LineStatus struct {
+	Kong   KongState
+	Po     PoState
+	Chong  ChongState
+	Moving bool
+	Strong bool // 月建旺相, 或日辰临扶生之
+}
+
+// Empty 是否作空论 (真空、假空); 动不为空与冲空则实不作空论
+func (s LineStatus) Empty() bool {
+	return s.Kong == KongZhen || s.Kong == KongJia
+}
+
+// Broken 是否作破论 (月破未补或日破)
+func (s LineStatus) Broken() bool {
+	return s.Po == PoYue || s.Chong == ChongRiPo
+}
+
+func (s LineStatus) String() string {
+	var parts []string
+	for _, label := range []string{s.Po.String(), s.Kong.String(), s.Chong.String()} {
+		if label != "" {
+			parts = append(parts, label)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// EvaluateLineStatus 依次论日冲、月破、旬空, 得出一爻的空破状态
+//   - 日冲: 动爻为日冲; 静爻旺相 (得月建或日辰之力) 为暗动, 休囚为日破
+//   - 月破: 逢日合者破而得补
+//   - 旬空: 动不为空; 旺相逢日冲, 冲空则实; 空而又破 (月破、日破) 或休囚静空为真空;
+//     旺相或得日生扶之空为假空
+func EvaluateLineStatus(ganzhi string, isMoving bool, monthZhi, dayZhi, dayXunKong string) LineStatus {
+	s := LineStatus{Moving: isMoving}
+	zhi := zhiOf(ganzhi)
+	if zhi == "" {
+		return s
+	}
+
+	wuxing := GetWuXing(zhi)
+	s.Strong = IsStrong(GetMonthStrength(wuxing, GetWuXing(monthZhi))) ||
+		IsStrong(GetDayStrength(wuxing, GetWuXing(dayZhi)))
+
+	// 日冲
+	if IsChong(dayZhi, zhi) {
+		switch {
+		case isMoving:
+			s.Chong = ChongRiChong
+		case s.Strong:
+			s.Chong = ChongAnDong
+		default:
+			s.Chong = ChongRiPo
+		}
+	}
+
+	// 月破
+	if IsChong(monthZhi, zhi) {
+		s.Po = PoYue
+		if CheckLiuHe(dayZhi, zhi) != "" {
+			s.Po = PoYueHe
+		}
+	}
+
+	// 旬空
+	if CheckXunKong(ganzhi, dayXunKong) {
+		switch {
+		case isMoving:
+			s.Kong = KongDong
+		case s.Chong == ChongAnDong:
+			s.Kong = KongChong
+		case s.Broken():
+			s.Kong = KongZhen
+		case s.Strong:
+			s.Kong = KongJia
+		default:
+			s.Kong = KongZhen
+		}
+	}
+	return s
+}
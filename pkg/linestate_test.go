@@ -0,0 +1,78 @@
+package pkg
+
+import "testing"
+
+func TestEvaluateLineStatus(t *testing.T) {
+	tests := []struct {
+		name             string
+		ganzhi           string
+		moving           bool
+		month, day, kong string
+		want             LineStatus
+	}{
+		{"休囚静空为真空", "甲戌", false, "卯", "寅", "戌亥", LineStatus{Kong: KongZhen}},
+		{"旺相之空为假空", "甲戌", false, "午", "寅", "戌亥", LineStatus{Kong: KongJia, Strong: true}},
+		{"动不为空", "甲戌", true, "卯", "寅", "戌亥", LineStatus{Kong: KongDong, Moving: true}},
+		{"冲空则实", "甲戌", false, "午", "辰", "戌亥", LineStatus{Kong: KongChong, Chong: ChongAnDong, Strong: true}},
+		{"破而又空为真空", "甲戌", false, "辰", "寅", "戌亥", LineStatus{Kong: KongZhen, Po: PoYue, Strong: true}},
+		{"月破逢合", "壬午", false, "子", "未", "寅卯", LineStatus{Po: PoYueHe}},
+		{"日破", "壬午", false, "亥", "子", "寅卯", LineStatus{Chong: ChongRiPo}},
+		{"月上休囚而日辰扶之为暗动", "甲戌", false, "寅", "辰", "申酉", LineStatus{Chong: ChongAnDong, Strong: true}},
+		{"动爻日冲", "壬午", true, "巳", "子", "寅卯", LineStatus{Chong: ChongRiChong, Moving: true, Strong: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EvaluateLineStatus(tt.ganzhi, tt.moving, tt.month, tt.day, tt.kong)
+			if got != tt.want {
+				t.Errorf("got %+v (%s), want %+v (%s)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateStrength_KongStates(t *testing.T) {
+	// 卯月寅日甲戌旬空: 静而休囚为真空, 动则不为空
+	score := func(moving bool) (total int, kinds []FindingKind) {
+		var bian *GuaInfo
+		if moving {
+			bian = &GuaInfo{Ganzhi: "甲戌"}
+		}
+		_, findings := CalculateStrength(GuaInfo{Ganzhi: "甲戌"}, bian, moving, "卯", "寅", "戌亥")
+		for _, f := range findings {
+			total += f.Score
+			kinds = append(kinds, f.Kind)
+		}
+		return total, kinds
+	}
+
+	static, staticKinds := score(false)
+	moving, movingKinds := score(true)
+	if static-moving != DefaultProfile.ZhenKong {
+		t.Errorf("static %d vs moving %d: expected difference of ZhenKong %d (%v / %v)",
+			static, moving, DefaultProfile.ZhenKong, staticKinds, movingKinds)
+	}
+}
+
+func TestChartBranches_AnDongMatchesLineStatus(t *testing.T) {
+	// 乾为天 上爻壬戌: 寅月休囚, 辰日冲而比和扶之, 为暗动
+	ctx := qianCareerContext()
+	ctx.MonthZhi, ctx.DayZhi = "寅", "辰"
+	guaInfo, err := GetGuaInfo(ctx.GuaHexagram, ctx.DayGan)
+	if err != nil {
+		t.Fatalf("GetGuaInfo failed: %v", err)
+	}
+	var result AnalysisResult
+	c := newChart(ctx, &result, guaInfo)
+	for _, b := range c.Branches() {
+		if b.Line < 0 || b.IsBian {
+			continue
+		}
+		status := EvaluateLineStatus(guaInfo[b.Line].Ganzhi, false, ctx.MonthZhi, ctx.DayZhi, ctx.DayXunKong)
+		if b.IsAnDong != (status.Chong == ChongAnDong) {
+			t.Errorf("%s IsAnDong = %v, line status %s", b.Source, b.IsAnDong, status)
+		}
+		if b.Line == 5 && !b.IsAnDong {
+			t.Error("expected 上爻壬戌 暗动")
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package pkg
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/6tail/lunar-go/calendar"
+)
+
+// MeihuaCast 梅花易数起卦的计算过程
+type MeihuaCast struct {
+	Year   int // 年支数 (子1 ... 亥12)
+	Month  int // 农历月数
+	Day    int // 农历日数
+	Hour   int // 时支数 (子1 ... 亥12)
+	Upper  int // 上卦先天数 (乾1 兑2 离3 震4 巽5 坎6 艮7 坤8)
+	Lower  int // 下卦先天数
+	Moving int // 动爻 (1-6, 自初爻起)
+}
+
+func (m MeihuaCast) String() string {
+	return fmt.Sprintf("年%d 月%d 日%d 时%d -> 上卦%s(%d) 下卦%s(%d) 动爻%d",
+		m.Year, m.Month, m.Day, m.Hour,
+		trigrams[m.Upper-1].name, m.Upper, trigrams[m.Lower-1].name, m.Lower, m.Moving)
+}
+
+// NewGuaFromTrigrams 由上下卦先天数 (1-8) 与动爻 (1-6) 生成卦
+// 先天数: 乾1 兑2 离3 震4 巽5 坎6 艮7 坤8
+func NewGuaFromTrigrams(upper, lower, moving int) (Gua, error) {
+	if upper < 1 || upper > 8 || lower < 1 || lower > 8 {
+		return Gua{}, fmt.Errorf("卦数必须为1-8, 实际为上卦%d 下卦%d", upper, lower)
+	}
+	if moving < 1 || moving > 6 {
+		return Gua{}, fmt.Errorf("动爻必须为1-6, 实际为%d", moving)
+	}
+
+	// 二进制自初爻至上爻: 下卦在前, 上卦在后
+	hexagram := trigrams[lower-1].binary + trigrams[upper-1].binary
+
+	values := make([]int, 6)
+	for i := range values {
+		yang := hexagram[i] == '1'
+		switch {
+		case i == moving-1 && yang:
+			values[i] = 9
+		case i == moving-1:
+			values[i] = 6
+		case yang:
+			values[i] = 7
+		default:
+			values[i] = 8
+		}
+	}
+	return GenerateGuaFromLines(values)
+}
+
+// CastByTime 梅花易数年月日时起卦
+// (年支数+月+日) 除8取余得上卦, 再加时支数除8取余得下卦, 总数除6取余得动爻。
+// 年月日取农历, 年支与时支由 lunar-go 推算。
+func CastByTime(date time.Time) (Gua, MeihuaCast) {
+	solar := calendar.NewSolar(date.Year(), int(date.Month()), date.Day(), date.Hour(), date.Minute(), date.Second())
+	lunar := solar.GetLunar()
+
+	month := lunar.GetMonth()
+	if month < 0 { // 闰月按本月计
+		month = -month
+	}
+
+	m := MeihuaCast{
+		Year:  lunar.GetYearZhiIndex() + 1,
+		Month: month,
+		Day:   lunar.GetDay(),
+		Hour:  lunar.GetTimeZhiIndex() + 1,
+	}
+
+	sum := m.Year + m.Month + m.Day
+	m.Upper = remainder(sum, 8)
+	m.Lower = remainder(sum+m.Hour, 8)
+	m.Moving = remainder(sum+m.Hour, 6)
+
+	gua, _ := NewGuaFromTrigrams(m.Upper, m.Lower, m.Moving)
+	return gua, m
+}
+
+// remainder 取余, 整除时取除数本身 (梅花易数以 8/6 代 0)
+func remainder(n, d int) int {
+	r := n % d
+	if r <= 0 {
+		r += d
+	}
+	return r
+}
+
+// NumberCast 报数/字数起卦的计算过程
+type NumberCast struct {
+	Numbers []int // 所报之数 (字数起卦时为各部分的字数或笔画数)
+	Hour    int   // 加入的时支数 (子1 ... 亥12), 0 表示未加时辰
+	Upper   int   // 上卦先天数
+	Lower   int   // 下卦先天数
+	Moving  int   // 动爻 (1-6, 自初爻起)
+}
+
+func (n NumberCast) String() string {
+	hour := ""
+	if n.Hour > 0 {
+		hour = fmt.Sprintf(" 时%d", n.Hour)
+	}
+	return fmt.Sprintf("数%v%s -> 上卦%s(%d) 下卦%s(%d) 动爻%d",
+		n.Numbers, hour,
+		trigrams[n.Upper-1].name, n.Upper, trigrams[n.Lower-1].name, n.Lower, n.Moving)
+}
+
+// HourZhiNumber 返回某时刻的时支数 (子1 ... 亥12)
+func HourZhiNumber(date time.Time) int {
+	solar := calendar.NewSolar(date.Year(), int(date.Month()), date.Day(), date.Hour(), date.Minute(), date.Second())
+	return solar.GetLunar().GetTimeZhiIndex() + 1
+}
+
+// CastByNumbers 报数起卦
+// 一数: 本数除8得上卦, 加时支数 (若有) 除8得下卦;
+// 二数: 首数得上卦, 次数得下卦;
+// 三数: 首数得上卦, 次数得下卦, 三数参与动爻。
+// 动爻均以诸数之和 (加时支数) 除6取余。hour 为时支数 (1-12), 传 0 表示不加时辰。
+func CastByNumbers(hour int, nums ...int) (Gua, NumberCast, error) {
+	if len(nums) < 1 || len(nums) > 3 {
+		return Gua{}, NumberCast{}, fmt.Errorf("报数起卦需要1至3个数, 实际为%d个", len(nums))
+	}
+	if hour < 0 || hour > 12 {
+		return Gua{}, NumberCast{}, fmt.Errorf("时支数必须为1-12 (0表示不加时辰), 实际为%d", hour)
+	}
+
+	sum := 0
+	for _, n := range nums {
+		if n <= 0 {
+			return Gua{}, NumberCast{}, fmt.Errorf("所报之数必须为正整数, 实际为%d", n)
+		}
+		sum += n
+	}
+
+	c := NumberCast{Numbers: nums, Hour: hour}
+	c.Upper = remainder(nums[0], 8)
+	if len(nums) == 1 {
+		c.Lower = remainder(nums[0]+hour, 8)
+	} else {
+		c.Lower = remainder(nums[1], 8)
+	}
+	c.Moving = remainder(sum+hour, 6)
+
+	gua, err := NewGuaFromTrigrams(c.Upper, c.Lower, c.Moving)
+	return gua, c, err
+}
+
+// CastByPhrase 字数起卦
+// 将字句分为前后两半 (字数不均时上卦取少、下卦取多, 取"天轻地重"之义),
+// 前半之数得上卦, 后半之数得下卦, 总数 (加时支数) 除6得动爻。
+// strokes 为每个字的笔画数; 为空时以字数计。一两个字的字句须提供笔画数。
+func CastByPhrase(phrase string, strokes []int, hour int) (Gua, NumberCast, error) {
+	chars := make([]rune, 0, len(phrase))
+	for _, r := range phrase {
+		if !unicode.IsSpace(r) && !unicode.IsPunct(r) {
+			chars = append(chars, r)
+		}
+	}
+
+	if len(chars) == 0 {
+		return Gua{}, NumberCast{}, fmt.Errorf("字句为空")
+	}
+	if strokes != nil && len(strokes) != len(chars) {
+		return Gua{}, NumberCast{}, fmt.Errorf("笔画数个数(%d)与字数(%d)不符", len(strokes), len(chars))
+	}
+	if strokes == nil && len(chars) < 3 {
+		return Gua{}, NumberCast{}, fmt.Errorf("一两个字须以笔画起卦, 请提供每个字的笔画数")
+	}
+
+	// 一字: 以笔画分上下 (上卦取笔画之半, 下卦取其余)
+	if len(chars) == 1 {
+		upper := strokes[0] / 2
+		if upper == 0 {
+			return Gua{}, NumberCast{}, fmt.Errorf("笔画数必须大于1")
+		}
+		return CastByNumbers(hour, upper, strokes[0]-upper)
+	}
+
+	split := len(chars) / 2
+	count := func(from, to int) int {
+		if strokes == nil {
+			return to - from
+		}
+		n := 0
+		for _, s := range strokes[from:to] {
+			n += s
+		}
+		return n
+	}
+
+	return CastByNumbers(hour, count(0, split), count(split, len(chars)))
+}
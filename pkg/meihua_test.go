@@ -0,0 +1,134 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewGuaFromTrigrams(t *testing.T) {
+	// 上乾(1) 下坤(8), 初爻动 -> 天地否 化 天雷无妄
+	gua, err := NewGuaFromTrigrams(1, 8, 1)
+	if err != nil {
+		t.Fatalf("NewGuaFromTrigrams failed: %v", err)
+	}
+	if got := strings.Join(gua.BenGua, ""); got != "000111" {
+		t.Errorf("BenGua = %s, want 000111", got)
+	}
+	if got := strings.Join(gua.BianGua, ""); got != "100111" {
+		t.Errorf("BianGua = %s, want 100111", got)
+	}
+	if gua.Lines[0] != 6 {
+		t.Errorf("Lines[0] = %d, want 6 (老阴)", gua.Lines[0])
+	}
+
+	if _, err := NewGuaFromTrigrams(0, 8, 1); err == nil {
+		t.Error("expected error for upper trigram 0")
+	}
+	if _, err := NewGuaFromTrigrams(1, 8, 7); err == nil {
+		t.Error("expected error for moving line 7")
+	}
+}
+
+func TestCastByTime(t *testing.T) {
+	// 2024-02-10 12:00 = 甲辰年 正月初一 午时
+	// 辰5 + 1 + 1 = 7 -> 上卦艮(7)
+	// 7 + 午7 = 14 -> 下卦坎(6), 动爻 2
+	date := time.Date(2024, 2, 10, 12, 0, 0, 0, time.Local)
+	gua, m := CastByTime(date)
+
+	if m.Year != 5 || m.Month != 1 || m.Day != 1 || m.Hour != 7 {
+		t.Fatalf("unexpected lunar numbers: %+v", m)
+	}
+	if m.Upper != 7 || m.Lower != 6 || m.Moving != 2 {
+		t.Errorf("Upper/Lower/Moving = %d/%d/%d, want 7/6/2", m.Upper, m.Lower, m.Moving)
+	}
+	if got := DetermineGuaName(strings.Join(gua.BenGua, "")); got != "山水蒙" {
+		t.Errorf("BenGua = %s, want 山水蒙", got)
+	}
+
+	moving := 0
+	for i, changed := range gua.Changed {
+		if changed {
+			moving++
+			if i != 1 {
+				t.Errorf("unexpected moving line %d", i+1)
+			}
+		}
+	}
+	if moving != 1 {
+		t.Errorf("expected exactly one moving line, got %d", moving)
+	}
+}
+
+func TestCastByNumbers(t *testing.T) {
+	tests := []struct {
+		name   string
+		hour   int
+		nums   []int
+		upper  int
+		lower  int
+		moving int
+	}{
+		// 一数: 11 -> 上卦3, 加午时(7)=18 -> 下卦2, 动爻 18%6=6
+		{"One number with hour", 7, []int{11}, 3, 2, 6},
+		// 二数: 3 上离, 8 下坤, 11%6=5
+		{"Two numbers", 0, []int{3, 8}, 3, 8, 5},
+		// 二数加时: (3+8+2)%6=1
+		{"Two numbers with hour", 2, []int{3, 8}, 3, 8, 1},
+		// 三数: 9%8=1, 16%8=8, (9+16+5)%6=6
+		{"Three numbers", 0, []int{9, 16, 5}, 1, 8, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gua, c, err := CastByNumbers(tt.hour, tt.nums...)
+			if err != nil {
+				t.Fatalf("CastByNumbers failed: %v", err)
+			}
+			if c.Upper != tt.upper || c.Lower != tt.lower || c.Moving != tt.moving {
+				t.Errorf("got %d/%d/%d, want %d/%d/%d", c.Upper, c.Lower, c.Moving, tt.upper, tt.lower, tt.moving)
+			}
+			if !gua.Changed[tt.moving-1] {
+				t.Errorf("line %d should be moving", tt.moving)
+			}
+		})
+	}
+
+	if _, _, err := CastByNumbers(0); err == nil {
+		t.Error("expected error for no numbers")
+	}
+	if _, _, err := CastByNumbers(0, 1, 2, 3, 4); err == nil {
+		t.Error("expected error for four numbers")
+	}
+	if _, _, err := CastByNumbers(0, 0); err == nil {
+		t.Error("expected error for non-positive number")
+	}
+}
+
+func TestCastByPhrase(t *testing.T) {
+	// 五字: 上卦取少 (2字), 下卦取多 (3字), 动爻 5
+	_, c, err := CastByPhrase("今日问前程", nil, 0)
+	if err != nil {
+		t.Fatalf("CastByPhrase failed: %v", err)
+	}
+	if c.Upper != 2 || c.Lower != 3 || c.Moving != 5 {
+		t.Errorf("got %d/%d/%d, want 2/3/5", c.Upper, c.Lower, c.Moving)
+	}
+
+	// 二字笔画: 4 上震, 9 下乾, 13%6=1
+	_, c, err = CastByPhrase("天下", []int{4, 9}, 0)
+	if err != nil {
+		t.Fatalf("CastByPhrase failed: %v", err)
+	}
+	if c.Upper != 4 || c.Lower != 1 || c.Moving != 1 {
+		t.Errorf("got %d/%d/%d, want 4/1/1", c.Upper, c.Lower, c.Moving)
+	}
+
+	if _, _, err := CastByPhrase("天下", nil, 0); err == nil {
+		t.Error("expected error for two characters without strokes")
+	}
+	if _, _, err := CastByPhrase("今日问", []int{4}, 0); err == nil {
+		t.Error("expected error for mismatched stroke count")
+	}
+}
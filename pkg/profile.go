@@ -0,0 +1,204 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ScoringProfile 用神旺衰的计分方案
+// 各流派对日月、动变、空破的轻重看法不同, 以分值体现; 总分 >0 为强, =0 为中平, <0 为弱。
+// 月破逢日合者以 YuePo 之半计; 动不为空、冲空则实不计分。
+// 用神伏藏时以飞伏与伏神空破诸项另计; 三合、三会成局者按局与用神的关系加减;
+// 十二长生诸项合计达 ±2 时旺衰升降一等。
+type ScoringProfile struct {
+	ID          string `json:"id" yaml:"id"`                   // 标识, 如 "zengshan"
+	Name        string `json:"name" yaml:"name"`               // 名称, 如 "增删卜易"
+	Description string `json:"description" yaml:"description"` // 说明
+
+	MonthStrong int `json:"month_strong" yaml:"month_strong"` // 月建旺相
+	DayStrong   int `json:"day_strong" yaml:"day_strong"`     // 日辰旺相
+	HuiTouSheng int `json:"hui_tou_sheng" yaml:"hui_tou_sheng"`
+	JinShen     int `json:"jin_shen" yaml:"jin_shen"`
+	HuiTouKe    int `json:"hui_tou_ke" yaml:"hui_tou_ke"`
+	TuiShen     int `json:"tui_shen" yaml:"tui_shen"`
+	XieQi       int `json:"xie_qi" yaml:"xie_qi"` // 化泄
+	YuePo       int `json:"yue_po" yaml:"yue_po"`
+	YueHe       int `json:"yue_he" yaml:"yue_he"`
+	RiHe        int `json:"ri_he" yaml:"ri_he"`
+	RiHai       int `json:"ri_hai" yaml:"ri_hai"`
+	RiXing      int `json:"ri_xing" yaml:"ri_xing"`
+	XunKong     int `json:"xun_kong" yaml:"xun_kong"`   // 旬空 (假空)
+	ZhenKong    int `json:"zhen_kong" yaml:"zhen_kong"` // 真空: 休囚静空或空而又破
+	AnDong      int `json:"an_dong" yaml:"an_dong"`
+	RiPo        int `json:"ri_po" yaml:"ri_po"`
+	RiChong     int `json:"ri_chong" yaml:"ri_chong"` // 动爻逢日冲
+
+	FeiShengFu int `json:"fei_sheng_fu" yaml:"fei_sheng_fu"` // 飞生伏
+	FeiKeFu    int `json:"fei_ke_fu" yaml:"fei_ke_fu"`       // 飞克伏
+	FuShengFei int `json:"fu_sheng_fei" yaml:"fu_sheng_fei"` // 伏生飞, 伏神泄气
+	FuKeFei    int `json:"fu_ke_fei" yaml:"fu_ke_fei"`       // 伏克飞
+	FuShenKong int `json:"fu_shen_kong" yaml:"fu_shen_kong"` // 伏神旬空
+	FuShenPo   int `json:"fu_shen_po" yaml:"fu_shen_po"`     // 伏神月破
+
+	SanHe       int `json:"san_he" yaml:"san_he"`             // 三合实局含用神
+	SanHeSame   int `json:"san_he_same" yaml:"san_he_same"`   // 局与用神同五行
+	SanHeSheng  int `json:"san_he_sheng" yaml:"san_he_sheng"` // 局生用神
+	SanHeKe     int `json:"san_he_ke" yaml:"san_he_ke"`       // 局克用神
+	SanHui      int `json:"san_hui" yaml:"san_hui"`           // 三会实局含用神
+	SanHuiSame  int `json:"san_hui_same" yaml:"san_hui_same"`
+	SanHuiSheng int `json:"san_hui_sheng" yaml:"san_hui_sheng"`
+	SanHuiKe    int `json:"san_hui_ke" yaml:"san_hui_ke"`
+	BranchBoost int `json:"branch_boost" yaml:"branch_boost"` // 合会未成实局而生扶用神
+
+	RiChangSheng  int `json:"ri_chang_sheng" yaml:"ri_chang_sheng"` // 日上长生
+	RiDiWang      int `json:"ri_di_wang" yaml:"ri_di_wang"`
+	RiMu          int `json:"ri_mu" yaml:"ri_mu"` // 入日墓
+	RiJue         int `json:"ri_jue" yaml:"ri_jue"`
+	HuaChangSheng int `json:"hua_chang_sheng" yaml:"hua_chang_sheng"`
+	HuaDiWang     int `json:"hua_di_wang" yaml:"hua_di_wang"`
+	HuaSi         int `json:"hua_si" yaml:"hua_si"`
+	HuaMu         int `json:"hua_mu" yaml:"hua_mu"`
+	HuaJue        int `json:"hua_jue" yaml:"hua_jue"`
+	DongMu        int `json:"dong_mu" yaml:"dong_mu"`             // 入动墓
+	SuiGuiRuMu    int `json:"sui_gui_ru_mu" yaml:"sui_gui_ru_mu"` // 世持官鬼入墓
+	JueChuSheng   int `json:"jue_chu_sheng" yaml:"jue_chu_sheng"` // 临绝而得生
+}
+
+// DefaultProfile 默认计分方案 (本库原有权重)
+var DefaultProfile = ScoringProfile{
+	ID:          "default",
+	Name:        "默认",
+	Description: "本库默认权重",
+	MonthStrong: 2, DayStrong: 2,
+	HuiTouSheng: 3, JinShen: 3, HuiTouKe: -5, TuiShen: -5, XieQi: -2,
+	YuePo: -4, YueHe: 2, RiHe: 2, RiHai: -1, RiXing: -1,
+	XunKong: -1, ZhenKong: -3, AnDong: 1, RiPo: -3, RiChong: -1,
+	FeiShengFu: 2, FeiKeFu: -2, FuShengFei: -1, FuKeFei: 1, FuShenKong: -2, FuShenPo: -4,
+	SanHe: 4, SanHeSame: 3, SanHeSheng: 2, SanHeKe: -4,
+	SanHui: 5, SanHuiSame: 3, SanHuiSheng: 2, SanHuiKe: -5, BranchBoost: 1,
+	RiChangSheng: 1, RiDiWang: 1, RiMu: -2, RiJue: -2,
+	HuaChangSheng: 2, HuaDiWang: 2, HuaSi: -2, HuaMu: -2, HuaJue: -3,
+	DongMu: -2, SuiGuiRuMu: -3, JueChuSheng: 1,
+}
+
+// 内置流派方案
+var builtinProfiles = map[string]ScoringProfile{
+	DefaultProfile.ID: DefaultProfile,
+	// 野鹤重日辰, 以月破为无用, 旬空多以出空论而轻之, 暗动有力
+	"zengshan": {
+		ID:          "zengshan",
+		Name:        "增删卜易",
+		Description: "野鹤老人: 日辰与月建并重, 月破最凶, 旬空轻看",
+		MonthStrong: 2, DayStrong: 3,
+		HuiTouSheng: 3, JinShen: 2, HuiTouKe: -6, TuiShen: -3, XieQi: -1,
+		YuePo: -6, YueHe: 2, RiHe: 1, RiHai: 0, RiXing: -1,
+		XunKong: 0, ZhenKong: -2, AnDong: 2, RiPo: -3, RiChong: -1,
+		FeiShengFu: 2, FeiKeFu: -3, FuShengFei: -1, FuKeFei: 1, FuShenKong: -1, FuShenPo: -6,
+		SanHe: 5, SanHeSame: 3, SanHeSheng: 2, SanHeKe: -5,
+		SanHui: 5, SanHuiSame: 3, SanHuiSheng: 2, SanHuiKe: -5, BranchBoost: 1,
+		RiChangSheng: 2, RiDiWang: 2, RiMu: -3, RiJue: -2,
+		HuaChangSheng: 2, HuaDiWang: 2, HuaSi: -2, HuaMu: -3, HuaJue: -3,
+		DongMu: -2, SuiGuiRuMu: -3, JueChuSheng: 2,
+	},
+	// 王洪绪承旧法, 空破并重, 合冲刑害皆论
+	"bushi": {
+		ID:          "bushi",
+		Name:        "卜筮正宗",
+		Description: "王洪绪: 空破并重, 刑害皆论",
+		MonthStrong: 2, DayStrong: 2,
+		HuiTouSheng: 3, JinShen: 3, HuiTouKe: -5, TuiShen: -4, XieQi: -2,
+		YuePo: -4, YueHe: 2, RiHe: 2, RiHai: -1, RiXing: -2,
+		XunKong: -2, ZhenKong: -4, AnDong: 1, RiPo: -3, RiChong: -1,
+		FeiShengFu: 2, FeiKeFu: -2, FuShengFei: -1, FuKeFei: 1, FuShenKong: -3, FuShenPo: -4,
+		SanHe: 4, SanHeSame: 3, SanHeSheng: 2, SanHeKe: -4,
+		SanHui: 5, SanHuiSame: 3, SanHuiSheng: 2, SanHuiKe: -5, BranchBoost: 1,
+		RiChangSheng: 1, RiDiWang: 1, RiMu: -2, RiJue: -2,
+		HuaChangSheng: 2, HuaDiWang: 2, HuaSi: -2, HuaMu: -2, HuaJue: -3,
+		DongMu: -2, SuiGuiRuMu: -4, JueChuSheng: 1,
+	},
+	// 古法以月令为纲, 日辰次之, 重进退
+	"huozhulin": {
+		ID:          "huozhulin",
+		Name:        "火珠林",
+		Description: "麻衣道者: 月令为纲, 日辰次之, 重进退",
+		MonthStrong: 3, DayStrong: 1,
+		HuiTouSheng: 2, JinShen: 3, HuiTouKe: -4, TuiShen: -4, XieQi: -1,
+		YuePo: -4, YueHe: 1, RiHe: 1, RiHai: -1, RiXing: -1,
+		XunKong: -1, ZhenKong: -3, AnDong: 1, RiPo: -2, RiChong: -1,
+		FeiShengFu: 3, FeiKeFu: -3, FuShengFei: -1, FuKeFei: 1, FuShenKong: -2, FuShenPo: -4,
+		SanHe: 3, SanHeSame: 3, SanHeSheng: 2, SanHeKe: -3,
+		SanHui: 4, SanHuiSame: 3, SanHuiSheng: 2, SanHuiKe: -4, BranchBoost: 1,
+		RiChangSheng: 1, RiDiWang: 1, RiMu: -1, RiJue: -1,
+		HuaChangSheng: 2, HuaDiWang: 2, HuaSi: -1, HuaMu: -2, HuaJue: -2,
+		DongMu: -1, SuiGuiRuMu: -3, JueChuSheng: 1,
+	},
+}
+
+// ProfileIDs 返回内置方案的标识 (按字母序)
+func ProfileIDs() []string {
+	ids := make([]string, 0, len(builtinProfiles))
+	for id := range builtinProfiles {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// GetProfile 按标识或名称取内置方案, 如 "zengshan" 或 "增删卜易"
+func GetProfile(name string) (*ScoringProfile, error) {
+	for _, p := range builtinProfiles {
+		if strings.EqualFold(p.ID, name) || p.Name == name {
+			profile := p
+			return &profile, nil
+		}
+	}
+	return nil, fmt.Errorf("未知的计分方案 %q, 可选: %s", name, strings.Join(ProfileIDs(), ", "))
+}
+
+// LoadProfile 从 YAML (.yaml/.yml) 或 JSON (.json) 文件加载计分方案
+// 文件中未给出的权重沿用默认方案。
+func LoadProfile(path string) (*ScoringProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := DefaultProfile
+	profile.ID, profile.Name, profile.Description = "", "", ""
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &profile)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &profile)
+	default:
+		return nil, fmt.Errorf("不支持的计分方案文件 %q, 应为 .yaml/.yml/.json", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析计分方案 %s 失败: %v", path, err)
+	}
+
+	if profile.ID == "" {
+		profile.ID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if profile.Name == "" {
+		profile.Name = profile.ID
+	}
+	return &profile, nil
+}
+
+// ResolveProfile 取内置方案; 不是内置名称时按文件路径加载
+func ResolveProfile(nameOrPath string) (*ScoringProfile, error) {
+	if p, err := GetProfile(nameOrPath); err == nil {
+		return p, nil
+	}
+	if _, err := os.Stat(nameOrPath); err != nil {
+		return nil, fmt.Errorf("未知的计分方案 %q, 可选: %s, 或 YAML/JSON 文件路径", nameOrPath, strings.Join(ProfileIDs(), ", "))
+	}
+	return LoadProfile(nameOrPath)
+}
@@ -0,0 +1,104 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetProfile(t *testing.T) {
+	for _, name := range []string{"zengshan", "增删卜易", "ZengShan"} {
+		p, err := GetProfile(name)
+		if err != nil {
+			t.Fatalf("GetProfile(%q) failed: %v", name, err)
+		}
+		if p.Name != "增删卜易" {
+			t.Errorf("GetProfile(%q).Name = %s", name, p.Name)
+		}
+	}
+	if _, err := GetProfile("nope"); err == nil {
+		t.Error("GetProfile(nope) expected error")
+	}
+
+	// 返回副本, 修改不影响内置方案
+	p, _ := GetProfile("default")
+	p.MonthStrong = 100
+	if DefaultProfile.MonthStrong != 2 {
+		t.Error("DefaultProfile was mutated")
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "school.yaml")
+	jsonPath := filepath.Join(dir, "school.json")
+	os.WriteFile(yamlPath, []byte("name: 本门\nyue_po: -8\nxun_kong: 0\n"), 0o644)
+	os.WriteFile(jsonPath, []byte(`{"id": "mine", "month_strong": 4}`), 0o644)
+
+	p, err := LoadProfile(yamlPath)
+	if err != nil {
+		t.Fatalf("LoadProfile(yaml) failed: %v", err)
+	}
+	if p.ID != "school" || p.Name != "本门" || p.YuePo != -8 || p.XunKong != 0 {
+		t.Errorf("unexpected YAML profile: %+v", p)
+	}
+	if p.MonthStrong != DefaultProfile.MonthStrong {
+		t.Errorf("unset weight MonthStrong = %d, want default %d", p.MonthStrong, DefaultProfile.MonthStrong)
+	}
+
+	p, err = LoadProfile(jsonPath)
+	if err != nil {
+		t.Fatalf("LoadProfile(json) failed: %v", err)
+	}
+	if p.ID != "mine" || p.Name != "mine" || p.MonthStrong != 4 {
+		t.Errorf("unexpected JSON profile: %+v", p)
+	}
+
+	txtPath := filepath.Join(dir, "school.txt")
+	os.WriteFile(txtPath, []byte("month_strong: 4"), 0o644)
+	if _, err := LoadProfile(txtPath); err == nil {
+		t.Error("LoadProfile(.txt) expected error")
+	}
+}
+
+func TestAnalyze_Profile(t *testing.T) {
+	// 乾为天 四爻壬午官鬼, 子月月破, 寅日生之: 默认 -4+2 为弱
+	ctx := qianCareerContext()
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.Profile != DefaultProfile.Name || result.Strength != "弱" {
+		t.Errorf("default: Profile=%s Strength=%s", result.Profile, result.Strength)
+	}
+
+	// 月破不计的方案: 仅得日生, 为强
+	ctx.Profile = &ScoringProfile{Name: "不论月破", DayStrong: 2}
+	result, err = Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.Profile != "不论月破" || result.Strength != "强" {
+		t.Errorf("custom: Profile=%s Strength=%s", result.Profile, result.Strength)
+	}
+}
+
+func TestFuShenRule_Profile(t *testing.T) {
+	// 飞神甲寅木生伏神甲午火, 子月冲午为伏神月破
+	result := AnalysisResult{YongShen: "官鬼", YongShenYao: GuaInfo{Ganzhi: "甲寅", LiuQin: "妻财", FuShen: "官鬼:甲午"}}
+	tests := []struct {
+		profile ScoringProfile
+		want    int
+	}{
+		{DefaultProfile, DefaultProfile.FeiShengFu + DefaultProfile.FuShenPo},
+		{ScoringProfile{FeiShengFu: 1}, 1},
+	}
+	for _, tt := range tests {
+		p := tt.profile
+		c := &Chart{Ctx: qianCareerContext(), Result: &result, Profile: &p, IsFuShen: true}
+		fuShenRule(c)
+		if c.FuShenScore != tt.want {
+			t.Errorf("%s: FuShenScore = %d, want %d", p.Name, c.FuShenScore, tt.want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// YongShenYingYao 代占不相干之人, 以应爻为用神, 非六亲
+const YongShenYingYao = "应爻"
+
+// Relation 事主与求测者的关系: 自占, 或代他人占
+type Relation int
+
+const (
+	RelationSelf    Relation = iota // 自占
+	RelationSpouse                  // 配偶: 男取妻财, 女取官鬼
+	RelationChild                   // 子女、晚辈: 子孙
+	RelationParent                  // 父母、长辈: 父母
+	RelationSibling                 // 兄弟姐妹: 兄弟
+	RelationFriend                  // 朋友、同辈: 兄弟
+	RelationBoss                    // 上司、官长: 官鬼
+	RelationOther                   // 不相干之人: 应爻
+)
+
+var relationNames = []string{"self", "spouse", "child", "parent", "sibling", "friend", "boss", "other"}
+
+var relationCn = []string{"自己", "配偶", "子女", "父母", "兄弟", "朋友", "上司", "他人"}
+
+func (r Relation) String() string {
+	if r < 0 || int(r) >= len(relationNames) {
+		return ""
+	}
+	return relationNames[r]
+}
+
+// Cn 关系的中文名, 如 "子女"
+func (r Relation) Cn() string {
+	if r < 0 || int(r) >= len(relationCn) {
+		return ""
+	}
+	return relationCn[r]
+}
+
+// ParseRelation 解析事主关系, 可用英文 (如 "child") 或中文 (如 "子女")
+func ParseRelation(s string) (Relation, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return RelationSelf, nil
+	}
+	for i := range relationNames {
+		if s == relationNames[i] || s == relationCn[i] {
+			return Relation(i), nil
+		}
+	}
+	return 0, fmt.Errorf("无效的事主关系 %q, 可选: %s", s, strings.Join(relationNames, ", "))
+}
+
+// YongShen 代占时事主本人的用神; 自占时返回 false
+// gender 为求测者性别, 仅代占配偶时用到。
+func (r Relation) YongShen(gender string) (string, bool) {
+	switch r {
+	case RelationSpouse:
+		if gender == "Female" {
+			return GuanGui.String(), true
+		}
+		return QiCai.String(), true
+	case RelationChild:
+		return ZiSun.String(), true
+	case RelationParent:
+		return FuMu.String(), true
+	case RelationSibling, RelationFriend:
+		return XiongDi.String(), true
+	case RelationBoss:
+		return GuanGui.String(), true
+	case RelationOther:
+		return YongShenYingYao, true
+	}
+	return "", false
+}
+
+// DetermineSubjectYongShen 依求测事项与事主关系取用神
+// 事项以事主本人为用神 (如健康、出行、婚姻) 而代他人占时, 改取其人之六亲; 其余事项用神不变。
+func DetermineSubjectYongShen(category, gender string, rel Relation) string {
+	if y, proxy := rel.YongShen(gender); proxy {
+		if ci, ok := LookupCategory(category); ok && ci.Personal {
+			return y
+		}
+	}
+	return DetermineYongShen(category, gender)
+}
+
+// SubjectLine 事主之爻: 自占为世爻; 代占时用神即其人者取用神之爻,
+// 否则取其人六亲之爻, 卦中不现则仍以世爻论
+func SubjectLine(ctx AnalysisContext, guaInfo []GuaInfo, yongShen string, yongShenIndex int) int {
+	shi := -1
+	for i, info := range guaInfo {
+		if info.ShiYing == "世" {
+			shi = i
+		}
+	}
+	person, proxy := ctx.Relation.YongShen(ctx.Gender)
+	if !proxy {
+		return shi
+	}
+	if person == yongShen {
+		return yongShenIndex
+	}
+
+	// 事主另有其爻, 依两现之法取之, 不受指定用神爻位影响
+	lookup := ctx
+	lookup.YongShenLine = 0
+	index, candidates, _, err := SelectYongShen(lookup, guaInfo, person)
+	if err != nil || len(candidates) == 0 || candidates[0].FuShen {
+		return shi
+	}
+	return index
+}
@@ -0,0 +1,150 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRelation(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Relation
+	}{
+		{"", RelationSelf},
+		{"self", RelationSelf},
+		{"Child", RelationChild},
+		{"子女", RelationChild},
+		{"boss", RelationBoss},
+		{"他人", RelationOther},
+	}
+	for _, tt := range tests {
+		got, err := ParseRelation(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRelation(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ParseRelation("cousin"); err == nil {
+		t.Error("expected error for unknown relation")
+	}
+}
+
+func TestDetermineSubjectYongShen(t *testing.T) {
+	tests := []struct {
+		category string
+		gender   string
+		rel      Relation
+		want     string
+	}{
+		{CategoryHealth, "Male", RelationSelf, YongShenShiYao},
+		{CategoryHealth, "Female", RelationChild, "子孙"},
+		{CategoryHealth, "Male", RelationBoss, "官鬼"},
+		{CategoryHealth, "Male", RelationSpouse, "妻财"},
+		{CategoryHealth, "Female", RelationSpouse, "官鬼"},
+		{CategoryTravel, "Male", RelationOther, YongShenYingYao},
+		{CategoryMarriage, "Female", RelationSelf, "官鬼"},
+		{CategoryMarriage, "Female", RelationChild, "子孙"},
+		// 求财、官司等以事为用神, 代占不改
+		{CategoryWealth, "Male", RelationChild, "妻财"},
+		{CategoryLawsuit, "Male", RelationFriend, "官鬼"},
+	}
+	for _, tt := range tests {
+		if got := DetermineSubjectYongShen(tt.category, tt.gender, tt.rel); got != tt.want {
+			t.Errorf("DetermineSubjectYongShen(%s, %s, %s) = %s, want %s", tt.category, tt.gender, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyze_SubjectLine(t *testing.T) {
+	// 乾为天: 初爻甲子子孙, 三爻甲辰父母 (应), 四爻壬午官鬼, 上爻壬戌父母 (世)
+	tests := []struct {
+		category     string
+		rel          Relation
+		yongShen     int
+		subjectIndex int
+	}{
+		{CategoryHealth, RelationSelf, 5, 5},
+		{CategoryHealth, RelationChild, 0, 0},
+		{CategoryHealth, RelationOther, 2, 2},
+		{CategoryLawsuit, RelationSelf, 3, 5},
+		// 代占官司: 用神仍为官鬼, 事主为其人之爻
+		{CategoryLawsuit, RelationChild, 3, 0},
+	}
+	for _, tt := range tests {
+		ctx := qianCareerContext()
+		ctx.Category, ctx.Relation = tt.category, tt.rel
+		result, err := Analyze(ctx)
+		if err != nil {
+			t.Fatalf("Analyze(%s, %s) failed: %v", tt.category, tt.rel, err)
+		}
+		if result.YongShenIndex != tt.yongShen || result.SubjectIndex != tt.subjectIndex {
+			t.Errorf("%s/%s: 用神 %d 事主 %d, want %d/%d", tt.category, tt.rel,
+				result.YongShenIndex, result.SubjectIndex, tt.yongShen, tt.subjectIndex)
+		}
+	}
+}
+
+func TestAnalyze_HealthForChild(t *testing.T) {
+	// 母占子病: 子孙初爻甲子为用神, 亦为医药
+	ctx := qianCareerContext()
+	ctx.Category, ctx.Gender, ctx.Relation = CategoryHealth, "Female", RelationChild
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.Relation != "子女" {
+		t.Errorf("Relation = %q, want 子女", result.Relation)
+	}
+	h := result.Health
+	if h == nil {
+		t.Fatal("expected health reading")
+	}
+	if h.Self.Role != "子女" || h.Self.Index != 0 {
+		t.Errorf("Self = %s at %d, want 子女 at 0", h.Self.Role, h.Self.Index)
+	}
+	if h.Medicine.Index != 0 || h.Illness.Index != 3 {
+		t.Errorf("医药 at %d, 病症 at %d; want 0 and 3", h.Medicine.Index, h.Illness.Index)
+	}
+}
+
+func TestAnalyze_HealthForBoss(t *testing.T) {
+	// 占上司病: 官鬼为用神, 以忌神子孙论病
+	ctx := qianCareerContext()
+	ctx.Category, ctx.Relation = CategoryHealth, RelationBoss
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	h := result.Health
+	if h == nil || h.Self.Index != 3 {
+		t.Fatalf("expected 官鬼 at 3 as patient, got %+v", h)
+	}
+	if h.Illness.Role != RoleIllness || h.Illness.LiuQin != "子孙" {
+		t.Errorf("Illness = %s%s, want 病症子孙", h.Illness.Role, h.Illness.LiuQin)
+	}
+}
+
+func TestAnalyze_MarriageForChild(t *testing.T) {
+	ctx := qianCareerContext()
+	ctx.Category, ctx.Gender, ctx.Relation = CategoryMarriage, "Female", RelationChild
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.YongShen != "子孙" {
+		t.Errorf("用神 = %s, want 子孙", result.YongShen)
+	}
+	var subject bool
+	for _, f := range result.FindingsOf(FindingJudgment) {
+		if f.Subject == "事主" && len(f.Lines) == 1 && f.Lines[0] == 0 {
+			subject = true
+		}
+	}
+	if !subject {
+		t.Error("expected 事主 finding on 初爻")
+	}
+	for _, f := range result.FindingsOf(FindingJudgment) {
+		if strings.Contains(f.Text, "测婚") {
+			t.Errorf("代占婚姻不应作男女之论: %s", f.Text)
+		}
+	}
+}
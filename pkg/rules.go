@@ -0,0 +1,270 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Chart 规则运行时的盘面上下文
+// 用神已选定; 规则读取盘面、输出断卦要素, 也可调整 Result 中的旺衰与吉凶。
+type Chart struct {
+	Ctx      AnalysisContext
+	Result   *AnalysisResult
+	GuaInfo  []GuaInfo // 本卦各爻 (自初爻起)
+	BianInfo []GuaInfo // 变卦各爻, 六亲以本宫五行论; 无动爻时为 nil
+	YongShen string    // 用神六亲
+	Profile  *ScoringProfile
+
+	IsFuShen     bool   // 用神是否伏藏
+	FuShenGanzhi string // 伏神干支
+	FuShenScore  int    // 飞伏与日月对伏神的总评分, 由伏神规则写入
+}
+
+func newChart(ctx AnalysisContext, result *AnalysisResult, guaInfo []GuaInfo) *Chart {
+	c := &Chart{
+		Ctx:      ctx,
+		Result:   result,
+		GuaInfo:  guaInfo,
+		YongShen: result.YongShen,
+		Profile:  ctx.Profile,
+	}
+	if c.Profile == nil {
+		c.Profile = &DefaultProfile
+	}
+
+	if strings.Contains(result.YongShenYao.FuShen, result.YongShen) {
+		c.IsFuShen = true
+		if parts := strings.Split(result.YongShenYao.FuShen, ":"); len(parts) == 2 {
+			c.FuShenGanzhi = parts[1]
+		}
+	}
+
+	if c.HasMoving() {
+		palaceIndex, _, _ := GetGuaPalace(DetermineGuaName(ctx.GuaHexagram))
+		bianInfo, err := GetBianGuaInfo(ctx.BianHexagram, ctx.DayGan, GetPalaceWuXing(palaceIndex))
+		if err == nil {
+			c.BianInfo = bianInfo
+		}
+	}
+	return c
+}
+
+// YongShenGanzhi 用神干支: 伏藏时取伏神, 否则取用神之爻
+func (c *Chart) YongShenGanzhi() string {
+	if c.IsFuShen && c.FuShenGanzhi != "" {
+		return c.FuShenGanzhi
+	}
+	return c.Result.YongShenYao.Ganzhi
+}
+
+// HasMoving 卦中是否有发动之爻
+func (c *Chart) HasMoving() bool {
+	for _, moving := range c.Ctx.Changed {
+		if moving {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMoving 判断第 i 爻是否发动
+func (c *Chart) IsMoving(i int) bool {
+	return i >= 0 && i < len(c.Ctx.Changed) && c.Ctx.Changed[i]
+}
+
+// Rule 一条断卦规则
+type Rule interface {
+	// Name 规则名, 在规则集中唯一
+	Name() string
+	// Apply 检视盘面, 返回断卦要素
+	Apply(c *Chart) []Finding
+}
+
+type funcRule struct {
+	name string
+	fn   func(c *Chart) []Finding
+}
+
+func (r funcRule) Name() string             { return r.name }
+func (r funcRule) Apply(c *Chart) []Finding { return r.fn(c) }
+
+// NewRule 以函数构造规则
+func NewRule(name string, fn func(c *Chart) []Finding) Rule {
+	return funcRule{name: name, fn: fn}
+}
+
+// 默认规则名
+const (
+	RuleFuShen     = "fushen"     // 伏神
+	RuleStrength   = "strength"   // 用神旺衰
+	RuleLines      = "lines"      // 各爻详细分析
+	RuleMoving     = "moving"     // 动爻互动
+	RuleBureau     = "bureau"     // 三合三会
+	RuleXing       = "xing"       // 三刑、相刑、自刑
+	RuleFanFuYin   = "fanfuyin"   // 反吟伏吟
+	RuleChangSheng = "changsheng" // 十二长生: 墓、绝、生、旺
+	RuleXiangShen  = "xiangshen"  // 原神、忌神、仇神
+	RuleCategory   = "category"   // 求测事项的专用断法
+	RuleJudgment   = "judgment"   // 吉凶与应期
+)
+
+// RuleSet 有序的规则集
+type RuleSet struct {
+	rules []Rule
+}
+
+// NewRuleSet 以给定顺序构造规则集
+func NewRuleSet(rules ...Rule) *RuleSet {
+	return &RuleSet{rules: append([]Rule(nil), rules...)}
+}
+
+// DefaultRules 返回默认规则集; 每次调用返回新副本, 可放心修改
+// 旺衰类规则在前, 吉凶规则最后, 以便其读取前面规则调整后的旺衰。
+func DefaultRules() *RuleSet {
+	return NewRuleSet(
+		NewRule(RuleFuShen, fuShenRule),
+		NewRule(RuleStrength, strengthRule),
+		NewRule(RuleLines, linesRule),
+		NewRule(RuleMoving, movingRule),
+		NewRule(RuleBureau, bureauRule),
+		NewRule(RuleXing, xingRule),
+		NewRule(RuleFanFuYin, fanFuYinRule),
+		NewRule(RuleChangSheng, changShengRule),
+		NewRule(RuleXiangShen, xiangShenRule),
+		NewRule(RuleCategory, categoryRule),
+		NewRule(RuleJudgment, judgmentRule),
+	)
+}
+
+// Rules 返回规则 (按执行顺序)
+func (s *RuleSet) Rules() []Rule {
+	return append([]Rule(nil), s.rules...)
+}
+
+// Names 返回规则名 (按执行顺序)
+func (s *RuleSet) Names() []string {
+	names := make([]string, len(s.rules))
+	for i, r := range s.rules {
+		names[i] = r.Name()
+	}
+	return names
+}
+
+func (s *RuleSet) index(name string) int {
+	for i, r := range s.rules {
+		if r.Name() == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Register 追加规则; 同名规则已存在时原地替换
+func (s *RuleSet) Register(r Rule) {
+	if i := s.index(r.Name()); i != -1 {
+		s.rules[i] = r
+		return
+	}
+	s.rules = append(s.rules, r)
+}
+
+// RegisterBefore 将规则插入到名为 before 的规则之前
+func (s *RuleSet) RegisterBefore(before string, r Rule) error {
+	if s.index(r.Name()) != -1 {
+		return fmt.Errorf("规则 %q 已存在", r.Name())
+	}
+	i := s.index(before)
+	if i == -1 {
+		return fmt.Errorf("规则 %q 不存在", before)
+	}
+	s.rules = append(s.rules[:i], append([]Rule{r}, s.rules[i:]...)...)
+	return nil
+}
+
+// Disable 移除规则, 返回是否找到
+func (s *RuleSet) Disable(name string) bool {
+	i := s.index(name)
+	if i == -1 {
+		return false
+	}
+	s.rules = append(s.rules[:i], s.rules[i+1:]...)
+	return true
+}
+
+// Reorder 按 names 重新排列规则; names 须恰好包含全部规则
+func (s *RuleSet) Reorder(names ...string) error {
+	if len(names) != len(s.rules) {
+		return fmt.Errorf("规则数不符: 需要%d个, 实际为%d个", len(s.rules), len(names))
+	}
+	ordered := make([]Rule, 0, len(names))
+	seen := make(map[string]bool)
+	for _, name := range names {
+		i := s.index(name)
+		if i == -1 {
+			return fmt.Errorf("规则 %q 不存在", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("规则 %q 重复", name)
+		}
+		seen[name] = true
+		ordered = append(ordered, s.rules[i])
+	}
+	s.rules = ordered
+	return nil
+}
+
+// BranchSource 盘中一个地支的来源: 日月、本卦爻或变爻
+type BranchSource struct {
+	Zhi      string
+	Source   string
+	Line     int    // 爻位索引, 日月为 -1
+	LiuQin   string // 所临六亲, 日月为空
+	IsDay    bool
+	IsMonth  bool
+	IsDong   bool // Is a moving line in Ben Gua (Dong Yao)
+	IsBian   bool // Is a transformed line (Bian Yao)
+	IsAnDong bool // Is a static line activated by day/month clash
+}
+
+// Active 日月、动爻、变爻与暗动之爻有力作用他爻, 静爻不论
+func (b BranchSource) Active() bool {
+	return b.IsDay || b.IsMonth || b.IsDong || b.IsBian || b.IsAnDong
+}
+
+// Branches 收集盘中全部地支: 日建、月建、本卦六爻及动爻所化
+func (c *Chart) Branches() []BranchSource {
+	ctx := c.Ctx
+	var allBranches []BranchSource
+	allBranches = append(allBranches, BranchSource{Zhi: ctx.DayZhi, Source: "日建", Line: -1, IsDay: true})
+	allBranches = append(allBranches, BranchSource{Zhi: ctx.MonthZhi, Source: "月建", Line: -1, IsMonth: true})
+
+	for i, info := range c.GuaInfo {
+		zhi := zhiOf(info.Ganzhi)
+		isMoving := c.IsMoving(i)
+
+		// 暗动与各爻空破状态同出一源
+		status := EvaluateLineStatus(info.Ganzhi, isMoving, ctx.MonthZhi, ctx.DayZhi, ctx.DayXunKong)
+		isAnDong := status.Chong == ChongAnDong
+
+		allBranches = append(allBranches, BranchSource{
+			Zhi:      zhi,
+			Source:   info.Position,
+			Line:     i,
+			LiuQin:   info.LiuQin,
+			IsDong:   isMoving,
+			IsAnDong: isAnDong,
+		})
+
+		if isMoving && len(c.BianInfo) > i {
+			bian := c.BianInfo[i]
+			allBranches = append(allBranches, BranchSource{
+				Zhi:    zhiOf(bian.Ganzhi),
+				Source: info.Position + "变",
+				Line:   i,
+				LiuQin: bian.LiuQin,
+				IsBian: true,
+			})
+		}
+	}
+	return allBranches
+}
@@ -0,0 +1,749 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// fuShenRule 用神伏藏时, 论伏神之旺衰与飞伏生克
+func fuShenRule(c *Chart) []Finding {
+	ctx, result := c.Ctx, c.Result
+	var findings []Finding
+	add := func(f Finding) {
+		findings = append(findings, f)
+	}
+
+	// Phase 1.5: Fu Shen (Hidden Spirit) Handling
+	isFuShen := c.IsFuShen
+	var fuShenGanzhi string
+	fuShenScore := 0
+
+	if isFuShen {
+		fuShenParts := strings.Split(result.YongShenYao.FuShen, ":")
+		if len(fuShenParts) == 2 {
+			fuShenGanzhi = fuShenParts[1]
+			fuShenWuXing := GetWuXingFromGanZhi(fuShenGanzhi)
+			fuShenZhi := zhiOf(fuShenGanzhi)
+			feiShenWuXing := GetWuXingFromGanZhi(result.YongShenYao.Ganzhi)
+			monthWuXing := GetWuXing(ctx.MonthZhi)
+			dayWuXing := GetWuXing(ctx.DayZhi)
+
+			// 1. Month/Day Influence (Decisive) - Added to details first
+			monthStr := GetMonthStrength(fuShenWuXing, monthWuXing)
+			dayStr := GetDayStrength(fuShenWuXing, dayWuXing)
+			add(Finding{Kind: FindingFuShen, Subject: "伏神", Lines: []int{result.YongShenIndex},
+				Text: fmt.Sprintf("伏神月日影响: 月建(%s) %s, 日辰(%s) %s", monthWuXing, monthStr, dayWuXing, dayStr)})
+
+			// 2. Fei-Fu Relationship (Supportive)
+			feiToFuRelation := GetRelation(feiShenWuXing, fuShenWuXing)
+			fuToFeiRelation := GetRelation(fuShenWuXing, feiShenWuXing)
+
+			fuShenDetail := fmt.Sprintf("飞伏关系: 飞神 %s (%s) ", result.YongShenYao.LiuQin, result.YongShenYao.Ganzhi)
+			feiFuScore := 0
+			if feiToFuRelation == "Sheng" {
+				fuShenDetail += "生 伏神 -> 飞生伏 (吉)"
+				feiFuScore = c.Profile.FeiShengFu
+			} else if feiToFuRelation == "Ke" {
+				fuShenDetail += "克 伏神 -> 飞克伏 (凶)"
+				feiFuScore = c.Profile.FeiKeFu
+			} else if fuToFeiRelation == "Sheng" {
+				fuShenDetail += "被 伏神 生 -> 伏生飞 (泄气)"
+				feiFuScore = c.Profile.FuShengFei
+			} else if fuToFeiRelation == "Ke" {
+				fuShenDetail += "被 伏神 克 -> 伏克飞 (吉)"
+				feiFuScore = c.Profile.FuKeFei
+			} else {
+				fuShenDetail += "与 伏神 比和/无生克"
+			}
+			fuShenScore += feiFuScore
+			add(Finding{Kind: FindingFuShen, Subject: "伏神", Lines: []int{result.YongShenIndex},
+				Direction: directionOf(feiFuScore), Score: feiFuScore, Text: fuShenDetail})
+
+			// 3. Special States
+			if CheckXunKong(fuShenGanzhi, ctx.DayXunKong) {
+				score := c.Profile.FuShenKong
+				add(Finding{Kind: FindingXunKong, Subject: "伏神", Lines: []int{result.YongShenIndex},
+					Direction: directionOf(score), Score: score, Text: "伏神特殊状态: 旬空"})
+				fuShenScore += score
+			}
+			if IsChong(ctx.MonthZhi, fuShenZhi) {
+				score := c.Profile.FuShenPo
+				add(Finding{Kind: FindingYuePo, Subject: "伏神", Lines: []int{result.YongShenIndex},
+					Direction: directionOf(score), Score: score, Text: "伏神特殊状态: 月破"})
+				fuShenScore += score
+			}
+		}
+	}
+	c.FuShenScore = fuShenScore
+
+	return findings
+}
+
+// strengthRule 以月建、日辰、动变论用神旺衰
+func strengthRule(c *Chart) []Finding {
+	ctx, result := c.Ctx, c.Result
+	isFuShen, fuShenGanzhi, fuShenScore := c.IsFuShen, c.FuShenGanzhi, c.FuShenScore
+	var findings []Finding
+	add := func(f Finding) {
+		findings = append(findings, f)
+	}
+
+	// Phase 2: Strength Analysis
+	// Need Bian Gua Info for the Use God Line
+	var bianYaoInfo *GuaInfo
+	isMoving := c.IsMoving(result.YongShenIndex)
+	if isMoving && len(c.BianInfo) > result.YongShenIndex {
+		bianYaoInfo = &c.BianInfo[result.YongShenIndex]
+	}
+
+	// Use virtual YaoInfo if Fu Shen
+	targetYaoInfo := result.YongShenYao
+	if isFuShen && fuShenGanzhi != "" {
+		targetYaoInfo.Ganzhi = fuShenGanzhi
+		targetYaoInfo.FuShen = "" // Don't trigger recursive Fu Shen checks in CalculateStrength
+	}
+
+	strength, strengthFindings := c.Profile.CalculateStrength(targetYaoInfo, bianYaoInfo, isMoving, ctx.MonthZhi, ctx.DayZhi, ctx.DayXunKong)
+
+	// Apply Fu Shen score adjustment if applicable
+	if isFuShen {
+		if fuShenScore >= 2 {
+			strength = raiseStrength(strength, "飞神生助")
+		} else if fuShenScore <= -2 {
+			strength = lowerStrength(strength, "飞神克制")
+		}
+	}
+
+	result.Strength = strength
+	strengthSubject := "用神"
+	if isFuShen {
+		strengthSubject = "伏神"
+	}
+	for _, f := range strengthFindings {
+		f.Subject = strengthSubject
+		f.Lines = []int{result.YongShenIndex}
+		add(f)
+	}
+
+	return findings
+}
+
+// linesRule 自上而下逐爻分析日月、动变、爻间冲合与伏神
+func linesRule(c *Chart) []Finding {
+	ctx := c.Ctx
+	guaInfo, bianGuaInfoAll := c.GuaInfo, c.BianInfo
+	var findings []Finding
+	add := func(f Finding) {
+		findings = append(findings, f)
+	}
+
+	// Comprehensive Line-by-Line Analysis
+
+	monthWuXing := GetWuXing(ctx.MonthZhi)
+	dayWuXing := GetWuXing(ctx.DayZhi)
+
+	// Analyze each line from top to bottom
+	for i := len(guaInfo) - 1; i >= 0; i-- {
+		lineInfo := guaInfo[i]
+		lineWuXing := GetWuXingFromGanZhi(lineInfo.Ganzhi)
+		lineZhi := zhiOf(lineInfo.Ganzhi)
+
+		lineDetail := fmt.Sprintf("%s:", lineInfo.Position)
+
+		// Month relationship
+		if ctx.MonthZhi == lineZhi {
+			lineDetail += "值月建"
+		} else if IsSheng(monthWuXing, lineWuXing) {
+			lineDetail += "月建生"
+		} else if IsKe(monthWuXing, lineWuXing) {
+			lineDetail += "月建克"
+		} else {
+			monthStrength := GetMonthStrength(lineWuXing, monthWuXing)
+			lineDetail += fmt.Sprintf("月上%s", monthStrength)
+		}
+
+		// Day relationship
+		if ctx.DayZhi == lineZhi {
+			lineDetail += " 临日辰"
+		} else if IsSheng(dayWuXing, lineWuXing) {
+			lineDetail += " 日辰生"
+		} else if IsKe(dayWuXing, lineWuXing) {
+			lineDetail += " 日辰克"
+		} else if IsChong(ctx.DayZhi, lineZhi) {
+			lineDetail += " 日辰冲"
+		}
+
+		// 空破冲: 月破、真空假空、暗动日破等
+		status := EvaluateLineStatus(lineInfo.Ganzhi, c.IsMoving(i), ctx.MonthZhi, ctx.DayZhi, ctx.DayXunKong)
+		if label := status.String(); label != "" {
+			lineDetail += " " + label
+		}
+
+		// Advanced Relationships with Day/Month
+		if he := CheckLiuHe(lineZhi, ctx.MonthZhi); he != "" {
+			lineDetail += fmt.Sprintf(" 月合(%s)", he)
+		}
+		if he := CheckLiuHe(lineZhi, ctx.DayZhi); he != "" {
+			lineDetail += fmt.Sprintf(" 六合(%s)", he)
+		}
+
+		if xing := CheckXing(lineZhi, ctx.DayZhi); xing != "" {
+			lineDetail += fmt.Sprintf(" 日%s", xing)
+		}
+		if CheckLiuHai(lineZhi, ctx.DayZhi) {
+			lineDetail += " 日害"
+		}
+
+		// Check for moving line
+		if len(ctx.Changed) > i && ctx.Changed[i] {
+			if len(bianGuaInfoAll) > i {
+				bianLineInfo := bianGuaInfoAll[i]
+				bianLineWuXing := GetWuXingFromGanZhi(bianLineInfo.Ganzhi)
+
+				// Check moving -> changed relationship (动爻对变爻的关系)
+				movingToChangedRelation := GetRelation(lineWuXing, bianLineWuXing)
+				// Check changed -> moving relationship (变爻对动爻的关系)
+				changedToMovingRelation := GetRelation(bianLineWuXing, lineWuXing)
+
+				if changedToMovingRelation == "Sheng" {
+					lineDetail += " 化回头生(变生动)"
+				} else if changedToMovingRelation == "Ke" {
+					lineDetail += " 化回头克(变克动)"
+				} else if movingToChangedRelation == "Sheng" {
+					lineDetail += " 化泄气(动生变)"
+				} else if movingToChangedRelation == "Ke" {
+					lineDetail += " 化克(动克变)"
+				}
+
+				jinTui := CheckJinTui(lineInfo.Ganzhi, bianLineInfo.Ganzhi)
+				if jinTui == "Jin Shen" {
+					lineDetail += " 化进神"
+				} else if jinTui == "Tui Shen" {
+					lineDetail += " 化退神"
+				}
+			}
+		}
+
+		// Check interactions with other lines
+		interactionCount := 0
+		for j := len(guaInfo) - 1; j >= 0; j-- {
+			if i == j {
+				continue
+			}
+			otherInfo := guaInfo[j]
+			otherWuXing := GetWuXingFromGanZhi(otherInfo.Ganzhi)
+			otherZhi := zhiOf(otherInfo.Ganzhi)
+
+			// Check Chong
+			if IsChong(lineZhi, otherZhi) {
+				lineDetail += fmt.Sprintf(" 冲%s", otherInfo.Position)
+				interactionCount++
+			}
+
+			// Check Sheng/Ke from other moving lines
+			if len(ctx.Changed) > j && ctx.Changed[j] {
+				relation := GetRelation(otherWuXing, lineWuXing)
+				if relation == "Sheng" {
+					lineDetail += fmt.Sprintf(" %s生", otherInfo.Position)
+					interactionCount++
+				} else if relation == "Ke" {
+					lineDetail += fmt.Sprintf(" %s克", otherInfo.Position)
+					interactionCount++
+				}
+
+				// Check Advanced Relations with Moving Lines
+				if he := CheckLiuHe(lineZhi, otherZhi); he != "" {
+					lineDetail += fmt.Sprintf(" %s合(%s)", otherInfo.Position, he)
+				}
+				if CheckLiuHai(lineZhi, otherZhi) {
+					lineDetail += fmt.Sprintf(" %s害", otherInfo.Position)
+				}
+				if xing := CheckXing(lineZhi, otherZhi); xing != "" {
+					lineDetail += fmt.Sprintf(" %s%s", otherInfo.Position, xing)
+				}
+			}
+		}
+
+		// Add changed line info for moving lines
+		if len(ctx.Changed) > i && ctx.Changed[i] && len(bianGuaInfoAll) > i {
+			bianLineInfo := bianGuaInfoAll[i]
+			bianLineWuXing := GetWuXingFromGanZhi(bianLineInfo.Ganzhi)
+			bianLineZhi := zhiOf(bianLineInfo.Ganzhi)
+
+			lineDetail += fmt.Sprintf("\n  变爻→%s %s", bianLineInfo.LiuQin, bianLineInfo.Ganzhi)
+
+			if ctx.MonthZhi == bianLineZhi {
+				lineDetail += " 值月建"
+			} else if IsSheng(monthWuXing, bianLineWuXing) {
+				lineDetail += " 月建生"
+			}
+
+			if ctx.DayZhi == bianLineZhi {
+				lineDetail += " 临日辰"
+			} else if IsSheng(dayWuXing, bianLineWuXing) {
+				lineDetail += " 日辰生"
+			}
+		}
+
+		// Add hidden spirit info
+		if lineInfo.FuShen != "" {
+			fuShenParts := strings.Split(lineInfo.FuShen, ":")
+			if len(fuShenParts) == 2 {
+				fuShenGanzhi := fuShenParts[1]
+				fuShenWuXing := GetWuXingFromGanZhi(fuShenGanzhi)
+				feiShenWuXing := lineWuXing
+
+				lineDetail += fmt.Sprintf("\n  伏神→%s", lineInfo.FuShen)
+
+				// Month/Day relationship with Fu Shen
+				if IsKe(monthWuXing, fuShenWuXing) {
+					lineDetail += " 月建克"
+				}
+				if IsKe(dayWuXing, fuShenWuXing) {
+					lineDetail += " 日辰克"
+				}
+
+				// Fei Shen - Fu Shen relationship
+				feiToFuRelation := GetRelation(feiShenWuXing, fuShenWuXing)
+				if feiToFuRelation == "Sheng" {
+					lineDetail += " 飞生伏"
+				} else if feiToFuRelation == "Ke" {
+					lineDetail += " 飞克伏"
+				}
+
+				fuToFeiRelation := GetRelation(fuShenWuXing, feiShenWuXing)
+				if fuToFeiRelation == "Sheng" {
+					lineDetail += " 伏生飞"
+				} else if fuToFeiRelation == "Ke" {
+					lineDetail += " 伏克飞"
+				}
+
+				// Check if Fu Shen is in Xun Kong
+				if CheckXunKong(fuShenGanzhi, ctx.DayXunKong) {
+					lineDetail += " 旬空"
+				}
+			}
+		}
+
+		add(Finding{Kind: FindingLine, Subject: lineInfo.Position, Lines: []int{i}, Text: lineDetail})
+	}
+
+	return findings
+}
+
+// movingRule 动爻所化及其对用神的生克 (原神/忌神)
+func movingRule(c *Chart) []Finding {
+	ctx, result := c.Ctx, c.Result
+	guaInfo, bianGuaInfoAll := c.GuaInfo, c.BianInfo
+	var findings []Finding
+	add := func(f Finding) {
+		findings = append(findings, f)
+	}
+
+	// Advanced Phase 3: Yuan Shen / Ji Shen Interactions
+	// Check other moving lines and their relationships with changed lines
+	// 用神伏藏时以伏神论生克, 飞神发动亦作他爻论
+	yongShenWuXing := GetWuXingFromGanZhi(c.YongShenGanzhi())
+	for i, changed := range ctx.Changed {
+		if changed {
+			// This is a moving line
+			otherInfo := guaInfo[i]
+			otherWuXing := GetWuXingFromGanZhi(otherInfo.Ganzhi)
+
+			// Build detail string for this moving line
+			interactionDetail := fmt.Sprintf("动爻互动: %s %s (%s, %s)", otherInfo.Position, otherInfo.Ganzhi, otherInfo.LiuQin, otherWuXing)
+
+			// Check relationship with its changed line
+			if len(bianGuaInfoAll) > i {
+				bianOtherInfo := bianGuaInfoAll[i]
+				bianOtherWuXing := GetWuXingFromGanZhi(bianOtherInfo.Ganzhi)
+
+				// Check moving -> changed relationship (动爻对变爻的关系)
+				movingToChangedRelation := GetRelation(otherWuXing, bianOtherWuXing)
+				// Check changed -> moving relationship (变爻对动爻的关系)
+				changedToMovingRelation := GetRelation(bianOtherWuXing, otherWuXing)
+
+				// Build transformation description
+				interactionDetail += fmt.Sprintf(" 化 %s (%s, %s)", bianOtherInfo.Ganzhi, bianOtherInfo.LiuQin, bianOtherWuXing)
+
+				// Check for Jin Shen / Tui Shen
+				jinTui := CheckJinTui(otherInfo.Ganzhi, bianOtherInfo.Ganzhi)
+				if jinTui != "" {
+					jinTuiCn := "进神"
+					if jinTui == "Tui Shen" {
+						jinTuiCn = "退神"
+					}
+					interactionDetail += fmt.Sprintf(", %s", jinTuiCn)
+				}
+
+				// Add Sheng/Ke relationship with traditional term and clear direction
+				if changedToMovingRelation == "Sheng" {
+					// Changed generates moving: 回头生(变生动)
+					interactionDetail += ", 回头生(变生动)"
+				} else if changedToMovingRelation == "Ke" {
+					// Changed controls moving: 回头克(变克动)
+					interactionDetail += ", 回头克(变克动)"
+				} else if movingToChangedRelation == "Sheng" {
+					// Moving generates changed: 泄气(动生变)
+					interactionDetail += ", 泄气(动生变)"
+				} else if movingToChangedRelation == "Ke" {
+					// Moving controls changed: 克变(动克变)
+					interactionDetail += ", 克变(动克变)"
+				}
+			}
+
+			// Add interaction with Use God (skip if this IS the Use God line)
+			if i != result.YongShenIndex || c.IsFuShen {
+				relation := GetRelation(otherWuXing, yongShenWuXing)
+
+				lines := []int{i, result.YongShenIndex}
+				if relation == "Sheng" {
+					interactionDetail += fmt.Sprintf(", 生 用神 (%s) -> 吉", yongShenWuXing)
+					add(Finding{Kind: FindingMoving, Subject: otherInfo.Position, Lines: lines, Direction: Favorable, Text: interactionDetail})
+				} else if relation == "Ke" {
+					interactionDetail += fmt.Sprintf(", 克 用神 (%s) -> 凶", yongShenWuXing)
+					add(Finding{Kind: FindingMoving, Subject: otherInfo.Position, Lines: lines, Direction: Unfavorable, Text: interactionDetail})
+				} else {
+					// No direct Sheng/Ke relationship with Use God, but still show the moving line
+					interactionDetail += fmt.Sprintf(", 与用神 (%s) 无直接生克", yongShenWuXing)
+					add(Finding{Kind: FindingMoving, Subject: otherInfo.Position, Lines: []int{i}, Text: interactionDetail})
+				}
+			} else {
+				// This IS the Use God line - just show its transformation
+				add(Finding{Kind: FindingMoving, Subject: "用神", Lines: []int{i}, Text: interactionDetail})
+			}
+		}
+	}
+
+	return findings
+}
+
+// bureauRule 三合、三会成局对用神的影响
+func bureauRule(c *Chart) []Finding {
+	result := c.Result
+	allBranches := c.Branches()
+
+	yongShenZhi := zhiOf(c.YongShenGanzhi())
+	yongShenWuXing := GetWuXingFromGanZhi(c.YongShenGanzhi())
+
+	// 三合成局者两支相见亦论半合、拱合; 三会无半会之说
+	findings, bureauInfluence := scanBureaus(sanHeGroups, FindingSanHe, "三合", c.Profile.sanHeScores(), true,
+		allBranches, yongShenZhi, yongShenWuXing)
+	hui, influence := scanBureaus(sanHuiGroups, FindingSanHui, "三会", c.Profile.sanHuiScores(), false,
+		allBranches, yongShenZhi, yongShenWuXing)
+	findings = append(findings, hui...)
+	bureauInfluence += influence
+
+	// Adjust Strength if bureau influence is significant
+	// 合局之力大, 弱者径转为强, 强者径转为弱
+	if bureauInfluence >= 3 {
+		switch strengthLevel(result.Strength) {
+		case -1:
+			result.Strength = "强 (合局生助)"
+		case 0:
+			result.Strength = "强"
+		}
+	} else if bureauInfluence <= -3 {
+		switch strengthLevel(result.Strength) {
+		case 1:
+			result.Strength = "弱 (合局克制)"
+		case 0:
+			result.Strength = "弱"
+		}
+	}
+
+	return findings
+}
+
+// bureauGroup 三合或三会的一组地支及所成五行
+type bureauGroup struct {
+	Branches []string
+	Element  string
+}
+
+// 三合: 生旺墓三支
+var sanHeGroups = []bureauGroup{
+	{[]string{"申", "子", "辰"}, "水"},
+	{[]string{"亥", "卯", "未"}, "木"},
+	{[]string{"寅", "午", "戌"}, "火"},
+	{[]string{"巳", "酉", "丑"}, "金"},
+}
+
+// 三会: 一方三支
+var sanHuiGroups = []bureauGroup{
+	{[]string{"亥", "子", "丑"}, "水"},
+	{[]string{"寅", "卯", "辰"}, "木"},
+	{[]string{"巳", "午", "未"}, "火"},
+	{[]string{"申", "酉", "戌"}, "金"},
+}
+
+// bureauScores 实局对用神的分值: 局中含用神、局与用神同五行、局生用神、局克用神;
+// Boost 为未成实局而生扶用神者
+type bureauScores struct {
+	YongShen, Same, Sheng, Ke, Boost int
+}
+
+func (p *ScoringProfile) sanHeScores() bureauScores {
+	return bureauScores{p.SanHe, p.SanHeSame, p.SanHeSheng, p.SanHeKe, p.BranchBoost}
+}
+
+func (p *ScoringProfile) sanHuiScores() bureauScores {
+	return bureauScores{p.SanHui, p.SanHuiSame, p.SanHuiSheng, p.SanHuiKe, p.BranchBoost}
+}
+
+// halfScore 取分值之半, 奇数向外取整, 如 3 作 2、-1 作 -1, 使半合拱合不因取整而失其吉凶
+func halfScore(n int) int {
+	switch {
+	case n > 0:
+		return (n + 1) / 2
+	case n < 0:
+		return (n - 1) / 2
+	}
+	return 0
+}
+
+// scanBureaus 逐组检查三合或三会, 返回断卦要素及对用神的总影响
+// 三支俱全者论实局或地支增强; partial 为真时两支相见者论半合、拱合
+func scanBureaus(groups []bureauGroup, kind FindingKind, name string, scores bureauScores, partial bool,
+	allBranches []BranchSource, yongShenZhi, yongShenWuXing string) ([]Finding, int) {
+	var findings []Finding
+	influence := 0
+
+	for _, g := range groups {
+		var members []BranchSource
+		for _, target := range g.Branches {
+			for _, b := range allBranches {
+				if b.Zhi == target {
+					members = append(members, b)
+					break
+				}
+			}
+		}
+
+		switch len(members) {
+		case 3:
+			numDongAn := 0
+			numDayMonth := 0
+			numBian := 0
+			var parts []string
+			var lines []int
+			containsYongShen := false
+
+			for _, m := range members {
+				label := m.Source
+				if m.IsAnDong {
+					label += "/暗动"
+					numDongAn++
+				} else if m.IsDong {
+					numDongAn++
+				} else if m.IsDay || m.IsMonth {
+					numDayMonth++
+				} else if m.IsBian {
+					numBian++
+				}
+
+				parts = append(parts, fmt.Sprintf("%s(%s)", m.Zhi, label))
+				if m.Line >= 0 {
+					lines = append(lines, m.Line)
+				}
+				if m.Zhi == yongShenZhi {
+					containsYongShen = true
+				}
+			}
+
+			// Shi Ju Check:
+			// 1. 3 Dong/AnDong
+			// 2. 2 Dong/AnDong + 1 Day/Month
+			// 3. 1 Dong/AnDong + 1 Bian + 1 Day/Month
+			isShiJu := (numDongAn == 3) ||
+				(numDongAn == 2 && numDayMonth >= 1) ||
+				(numDongAn == 1 && numBian >= 1 && numDayMonth >= 1)
+
+			if isShiJu {
+				score := 0
+				switch {
+				case containsYongShen:
+					score = scores.YongShen
+				case g.Element == yongShenWuXing:
+					score = scores.Same
+				case IsSheng(g.Element, yongShenWuXing):
+					score = scores.Sheng
+				case IsKe(g.Element, yongShenWuXing):
+					score = scores.Ke
+				}
+				influence += score
+				findings = append(findings, Finding{Kind: kind, Subject: g.Element, Lines: lines, Direction: directionOf(score), Score: score,
+					Text: fmt.Sprintf("%s%s实局: %s", name, g.Element, strings.Join(parts, " "))})
+			} else if numDongAn > 0 || numBian > 0 {
+				score := 0
+				if g.Element == yongShenWuXing || IsSheng(g.Element, yongShenWuXing) {
+					score = scores.Boost
+				}
+				influence += score
+				findings = append(findings, Finding{Kind: FindingBranchBoost, Subject: g.Element, Lines: lines, Direction: directionOf(score), Score: score,
+					Text: fmt.Sprintf("地支增强(%s之力): %s", g.Element, strings.Join(parts, " "))})
+			}
+		case 2:
+			if !partial {
+				continue
+			}
+			if f, ok := partialBureau(g.Branches, g.Element, allBranches, yongShenZhi, yongShenWuXing, scores); ok {
+				influence += f.Score
+				findings = append(findings, f)
+			}
+		}
+	}
+	return findings, influence
+}
+
+// partialBureau 三合缺一支: 生旺、旺墓为半合, 生墓拱其旺地为拱合
+// 半合须有日月或动爻参与, 拱合须日月居其一; 其力逊于三合实局。
+// scores 为三合实局的分值, 半合取其半, 拱合再取其半。
+func partialBureau(branches []string, element string, allBranches []BranchSource, yongShenZhi, yongShenWuXing string, scores bureauScores) (Finding, bool) {
+	var members []BranchSource
+	var present []int // 所见之支在 生、旺、墓 中的位置
+	for i, target := range branches {
+		if b, ok := pickBranch(allBranches, target); ok {
+			members = append(members, b)
+			present = append(present, i)
+		}
+	}
+	if len(members) != 2 {
+		return Finding{}, false
+	}
+
+	kind, name := FindingBanHe, "半合"
+	if present[0] == 0 && present[1] == 2 {
+		kind, name = FindingGongHe, "拱合"
+		if !members[0].IsDay && !members[0].IsMonth && !members[1].IsDay && !members[1].IsMonth {
+			return Finding{}, false
+		}
+	} else if !anyActive(members) {
+		return Finding{}, false
+	}
+
+	var parts []string
+	var lines []int
+	containsYongShen := false
+	for _, m := range members {
+		label := m.Source
+		if m.IsAnDong {
+			label += "/暗动"
+		}
+		parts = append(parts, fmt.Sprintf("%s(%s)", m.Zhi, label))
+		if m.Line >= 0 {
+			lines = append(lines, m.Line)
+		}
+		if m.Zhi == yongShenZhi {
+			containsYongShen = true
+		}
+	}
+
+	// 半合减三合实局之半, 拱合再减
+	score := 0
+	switch {
+	case containsYongShen:
+		score = halfScore(scores.YongShen)
+	case element == yongShenWuXing:
+		score = halfScore(scores.Same)
+	case IsSheng(element, yongShenWuXing):
+		score = halfScore(scores.Sheng)
+	case IsKe(element, yongShenWuXing):
+		score = halfScore(scores.Ke)
+	}
+	if kind == FindingGongHe {
+		score = halfScore(score)
+	}
+
+	text := fmt.Sprintf("%s%s局: %s", name, element, strings.Join(parts, " "))
+	if kind == FindingGongHe {
+		text += fmt.Sprintf(", 拱%s", branches[1])
+	}
+	return Finding{Kind: kind, Subject: element, Lines: lines, Direction: directionOf(score), Score: score, Text: text}, true
+}
+
+// fanFuYinRule 反吟伏吟
+func fanFuYinRule(c *Chart) []Finding {
+	ctx, result := c.Ctx, c.Result
+	var findings []Finding
+	add := func(f Finding) {
+		findings = append(findings, f)
+	}
+
+	// Phase 3.5: 反吟/伏吟
+	// 涉及用神者, 反吟主反复、伏吟主停滞, 均减其力
+	fanFuYin, err := DetectFanFuYin(ctx.GuaHexagram, ctx.BianHexagram, ctx.Changed)
+	if err == nil && len(fanFuYin) > 0 {
+		result.FanFuYin = fanFuYin
+		for _, f := range fanFuYin {
+			finding := Finding{Kind: fanFuYinKind(f.Kind), Subject: f.Position, Lines: f.Lines, Text: f.Description}
+			if f.involvesLine(result.YongShenIndex) {
+				finding.Subject = "用神"
+				finding.Direction = Unfavorable
+				finding.Text += " (涉及用神)"
+			}
+			add(finding)
+		}
+		for _, f := range fanFuYin {
+			if !f.involvesLine(result.YongShenIndex) {
+				continue
+			}
+			result.Strength = lowerStrength(result.Strength, "")
+			effect := "反复不定"
+			if f.Kind == "伏吟" {
+				effect = "迟滞难伸"
+			}
+			add(Finding{Kind: fanFuYinKind(f.Kind), Subject: "用神", Lines: []int{result.YongShenIndex}, Direction: Unfavorable,
+				Text: fmt.Sprintf("用神逢%s, 事主%s, 旺衰降一等", f.Kind, effect)})
+			break
+		}
+	}
+
+	return findings
+}
+
+// fanFuYinKind 反吟/伏吟对应的要素种类
+func fanFuYinKind(kind string) FindingKind {
+	if kind == "伏吟" {
+		return FindingFuYin
+	}
+	return FindingFanYin
+}
+
+// judgmentRule 按事项断吉凶、给出建议并推应期
+func judgmentRule(c *Chart) []Finding {
+	ctx, result := c.Ctx, c.Result
+
+	// Phase 4: Judgment & Timing
+	v := JudgeCategory(c)
+	result.Judgment = v.Judgment()
+	result.Advice = v.Advice
+	findings := v.Reasons
+
+	// Timing
+	target := result.YongShenYao
+	var bian *GuaInfo
+	moving := c.IsMoving(result.YongShenIndex)
+	if c.IsFuShen && c.FuShenGanzhi != "" {
+		// 伏神不动, 以伏神干支论
+		target.Ganzhi = c.FuShenGanzhi
+		moving = false
+	} else if moving && len(c.BianInfo) > result.YongShenIndex {
+		bian = &c.BianInfo[result.YongShenIndex]
+	}
+	events := PredictTiming(target, bian, moving, result.Strength, ctx)
+	if c.IsFuShen {
+		if fei, err := ZhiFromGanZhi(result.YongShenYao.Ganzhi); err == nil {
+			e := TimingEvent{Zhi: fei.Chong().String(), Reasons: []string{"伏神待冲开飞神之日出透"}}
+			if !ctx.Date.IsZero() {
+				e.Days = NextZhiDays(ctx.Date, fei.Chong(), timingDays)
+				e.Months = NextZhiMonths(ctx.Date, fei.Chong(), timingMonths)
+			}
+			events = append(events, e)
+		}
+	}
+	result.TimingEvents = events
+	result.Timing = FormatTiming(events)
+
+	return findings
+}
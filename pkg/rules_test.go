@@ -0,0 +1,170 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+)
+
+// qianCareerContext 乾为天静卦问事业, 子月寅日
+func qianCareerContext() AnalysisContext {
+	return AnalysisContext{
+		GuaHexagram:  "111111",
+		BianHexagram: "111111",
+		Changed:      make([]bool, 6),
+		DayGan:       "甲",
+		DayZhi:       "寅",
+		MonthZhi:     "子",
+		DayXunKong:   "戌亥",
+		Category:     CategoryCareer,
+		Gender:       "Male",
+	}
+}
+
+func TestRuleSet_DisableAndReorder(t *testing.T) {
+	rules := DefaultRules()
+	if !rules.Disable(RuleLines) {
+		t.Fatal("Disable(lines) returned false")
+	}
+	if rules.Disable("missing") {
+		t.Error("Disable(missing) returned true")
+	}
+
+	want := []string{RuleJudgment, RuleFuShen, RuleStrength, RuleMoving, RuleBureau, RuleXing, RuleFanFuYin, RuleChangSheng, RuleXiangShen, RuleCategory}
+	if err := rules.Reorder(want...); err != nil {
+		t.Fatalf("Reorder failed: %v", err)
+	}
+	if got := rules.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+
+	if err := rules.Reorder(RuleJudgment); err == nil {
+		t.Error("Reorder with missing rules expected error")
+	}
+	if err := rules.Reorder(RuleJudgment, RuleJudgment, RuleStrength, RuleMoving, RuleBureau, RuleXing, RuleFanFuYin, RuleChangSheng, RuleXiangShen, RuleCategory); err == nil {
+		t.Error("Reorder with duplicate rules expected error")
+	}
+
+	// DefaultRules 每次返回新副本
+	if len(DefaultRules().Names()) != 11 {
+		t.Error("DefaultRules was mutated")
+	}
+}
+
+func TestAnalyze_CustomRules(t *testing.T) {
+	rules := DefaultRules()
+	rules.Disable(RuleLines)
+	err := rules.RegisterBefore(RuleJudgment, NewRule("always-strong", func(c *Chart) []Finding {
+		c.Result.Strength = "强"
+		return []Finding{{Kind: "本派", Subject: "用神", Lines: []int{c.Result.YongShenIndex}, Direction: Favorable, Text: "本派规则: 用神作强论"}}
+	}))
+	if err != nil {
+		t.Fatalf("RegisterBefore failed: %v", err)
+	}
+
+	ctx := qianCareerContext()
+	ctx.Rules = rules
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if len(result.FindingsOf(FindingLine)) != 0 {
+		t.Error("expected no line findings with lines rule disabled")
+	}
+	if len(result.FindingsOf("本派")) != 1 {
+		t.Error("expected custom finding")
+	}
+	if result.Judgment != "吉" {
+		t.Errorf("Judgment = %s, want 吉 after custom rule", result.Judgment)
+	}
+}
+
+func TestNewChart_BianInfo(t *testing.T) {
+	ctx := qianCareerContext()
+	var result AnalysisResult
+	guaInfo, err := GetGuaInfo(ctx.GuaHexagram, ctx.DayGan)
+	if err != nil {
+		t.Fatalf("GetGuaInfo failed: %v", err)
+	}
+	if c := newChart(ctx, &result, guaInfo); c.HasMoving() || c.BianInfo != nil {
+		t.Errorf("静卦 BianInfo = %v, want nil", c.BianInfo)
+	}
+
+	ctx.BianHexagram = "011111"
+	ctx.Changed[0] = true
+	if c := newChart(ctx, &result, guaInfo); !c.HasMoving() || len(c.BianInfo) != 6 {
+		t.Errorf("动卦 BianInfo has %d lines, want 6", len(c.BianInfo))
+	}
+}
+
+func TestPartialBureau(t *testing.T) {
+	water := []string{"申", "子", "辰"}
+	day := BranchSource{Zhi: "申", Source: "日建", Line: -1, IsDay: true}
+	month := BranchSource{Zhi: "申", Source: "月建", Line: -1, IsMonth: true}
+	static := func(z string, i int) BranchSource { return BranchSource{Zhi: z, Source: yaoPositions[i], Line: i} }
+	moving := BranchSource{Zhi: "申", Source: "五爻", Line: 4, IsDong: true}
+
+	tests := []struct {
+		name     string
+		branches []BranchSource
+		kind     FindingKind
+		score    int
+	}{
+		{"日辰半合", []BranchSource{day, static("子", 3)}, FindingBanHe, 2},
+		{"静爻不合", []BranchSource{static("申", 4), static("子", 3)}, "", 0},
+		{"月建拱合", []BranchSource{month, static("辰", 2)}, FindingGongHe, 1},
+		{"动爻不拱", []BranchSource{moving, static("辰", 2)}, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 用神子水
+			f, ok := partialBureau(water, "水", tt.branches, "子", "水", DefaultProfile.sanHeScores())
+			if ok != (tt.kind != "") {
+				t.Fatalf("ok = %v, want %v", ok, tt.kind != "")
+			}
+			if ok && (f.Kind != tt.kind || f.Score != tt.score) {
+				t.Errorf("got %s %d, want %s %d (%s)", f.Kind, f.Score, tt.kind, tt.score, f.Text)
+			}
+		})
+	}
+
+	// 分值取自三合实局之半: 本门三合 6 分, 半合得 3; 水局克午火, 拱合仍为凶
+	f, _ := partialBureau(water, "水", []BranchSource{day, static("子", 3)}, "子", "水", bureauScores{YongShen: 6})
+	if f.Score != 3 {
+		t.Errorf("半合 Score = %d, want 3", f.Score)
+	}
+	f, _ = partialBureau(water, "水", []BranchSource{month, static("辰", 2)}, "午", "火", DefaultProfile.sanHeScores())
+	if f.Score != -1 || f.Direction != Unfavorable {
+		t.Errorf("拱合克用神 = %d %s, want -1 凶", f.Score, f.Direction)
+	}
+}
+
+func TestAnalyze_BanHe(t *testing.T) {
+	// 乾为天卯日亥月: 亥卯半合木局, 生用神午火
+	ctx := qianCareerContext()
+	ctx.DayZhi, ctx.MonthZhi = "卯", "亥"
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	fs := result.FindingsOf(FindingBanHe)
+	if len(fs) != 1 || fs[0].Subject != "木" || fs[0].Direction != Favorable {
+		t.Errorf("expected favorable 半合木局, got %v", fs)
+	}
+}
+
+func TestAnalyze_BureauAgainstFuShen(t *testing.T) {
+	// 山地剥问兄弟: 兄弟壬申伏于五爻丙子之下; 四爻丙戌动化午, 与寅日成三合火局, 克伏神申金
+	ctx := qianCareerContext()
+	ctx.GuaHexagram, ctx.BianHexagram = "000001", "000111"
+	ctx.Changed[3] = true
+	ctx.Category = CategorySiblings
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	sanHe := result.FindingsOf(FindingSanHe)
+	if len(sanHe) != 1 || sanHe[0].Score != DefaultProfile.SanHeKe {
+		t.Errorf("三合火局 = %v, want score %d against 伏神申金", sanHe, DefaultProfile.SanHeKe)
+	}
+}
@@ -0,0 +1,169 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/6tail/lunar-go/calendar"
+)
+
+// 每条应期列出的日、月个数
+const (
+	timingDays   = 2
+	timingMonths = 1
+)
+
+// TimingEvent 一条应期
+type TimingEvent struct {
+	Zhi     string      // 应期地支
+	Reasons []string    // 取应依据
+	Days    []time.Time // 起卦之后最近的该支日
+	Months  []time.Time // 起卦之后最近的该支月 (自交节之日起)
+}
+
+func (e TimingEvent) String() string {
+	s := fmt.Sprintf("%s日/%s月: %s", e.Zhi, e.Zhi, strings.Join(e.Reasons, "; "))
+	var dates []string
+	for _, d := range e.Days {
+		dates = append(dates, d.Format("2006-01-02"))
+	}
+	for _, m := range e.Months {
+		dates = append(dates, e.Zhi+"月 "+m.Format("2006-01-02")+"起")
+	}
+	if len(dates) > 0 {
+		s += fmt.Sprintf(" (%s)", strings.Join(dates, ", "))
+	}
+	return s
+}
+
+// FormatTiming 将应期合并为一行文本
+func FormatTiming(events []TimingEvent) string {
+	parts := make([]string, 0, len(events))
+	for _, e := range events {
+		parts = append(parts, e.String())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// lunarAt 取某日正午的农历 (避开子时换日)
+func lunarAt(d time.Time) *calendar.Lunar {
+	return calendar.NewSolar(d.Year(), int(d.Month()), d.Day(), 12, 0, 0).GetLunar()
+}
+
+// NextZhiDays 返回 from 之后 (不含当日) 最近 n 个地支为 zhi 的日子
+func NextZhiDays(from time.Time, zhi Zhi, n int) []time.Time {
+	var days []time.Time
+	// 日支十二日一轮
+	for i := 1; len(days) < n && i <= 12*n; i++ {
+		d := from.AddDate(0, 0, i)
+		if lunarAt(d).GetDayZhi() == zhi.String() {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// monthZhiBy 取某日结束时的月建; 交节多不在正午, 以当日最后一刻为准, 交节之日即属新月
+func monthZhiBy(d time.Time) string {
+	return calendar.NewSolar(d.Year(), int(d.Month()), d.Day(), 23, 59, 59).GetLunar().GetMonthZhiExact()
+}
+
+// NextZhiMonths 返回 from 之后最近 n 个月建为 zhi 的月份的交节之日
+// 起卦时已入该支之月不计, 取下一轮; 起卦当日稍后交节入该支之月, 取当日。
+func NextZhiMonths(from time.Time, zhi Zhi, n int) []time.Time {
+	var months []time.Time
+	prev := calendar.NewSolar(from.Year(), int(from.Month()), from.Day(), from.Hour(), from.Minute(), from.Second()).GetLunar().GetMonthZhiExact()
+	// 月建十二月一轮, 多留一月余量
+	for i := 0; len(months) < n && i <= 380*n; i++ {
+		d := from.AddDate(0, 0, i)
+		cur := monthZhiBy(d)
+		if cur == zhi.String() && prev != cur {
+			months = append(months, d)
+		}
+		prev = cur
+	}
+	return months
+}
+
+// PredictTiming 依古法推用神应期
+//   - 旬空: 出空逢值日填实, 逢冲则实
+//   - 月破: 出月逢填实之日
+//   - 逢合: 合处逢冲; 逢冲: 冲处逢合
+//   - 动爻: 逢值日
+//   - 入墓 (日墓、化墓): 逢冲墓之日
+//   - 以上皆无: 静而旺者逢冲, 静而衰者待值日旺相
+//
+// ctx.Date 不为零时, 以 lunar-go 推算起卦之后对应的具体日子与月份。
+func PredictTiming(yao GuaInfo, bian *GuaInfo, moving bool, strength string, ctx AnalysisContext) []TimingEvent {
+	yz, err := ZhiFromGanZhi(yao.Ganzhi)
+	if err != nil {
+		return nil
+	}
+	yw := yz.WuXing()
+	dayZ, errDay := ParseZhi(ctx.DayZhi)
+	monthZ, errMonth := ParseZhi(ctx.MonthZhi)
+
+	var events []TimingEvent
+	add := func(z Zhi, reason string) {
+		for i := range events {
+			if events[i].Zhi == z.String() {
+				events[i].Reasons = append(events[i].Reasons, reason)
+				return
+			}
+		}
+		events = append(events, TimingEvent{Zhi: z.String(), Reasons: []string{reason}})
+	}
+
+	var bz Zhi = -1
+	if moving && bian != nil {
+		if z, err := ZhiFromGanZhi(bian.Ganzhi); err == nil {
+			bz = z
+		}
+	}
+
+	if CheckXunKong(yao.Ganzhi, ctx.DayXunKong) {
+		add(yz, "旬空, 出空逢值日填实")
+		add(yz.Chong(), "旬空逢冲, 冲空则实")
+	}
+	if errMonth == nil && monthZ.Chong() == yz {
+		add(yz, "月破, 出月逢填实之日")
+	}
+	if (errMonth == nil && monthZ.He() == yz) || (errDay == nil && dayZ.He() == yz) {
+		add(yz.Chong(), "逢日月合住, 合处逢冲")
+	}
+	if bz >= 0 && bz.He() == yz {
+		add(yz.Chong(), "动而化合, 合处逢冲")
+	}
+	if errDay == nil && dayZ.Chong() == yz {
+		add(yz.He(), "逢日冲, 冲处逢合")
+	}
+	if moving {
+		add(yz, "动爻逢值日")
+	}
+	if mu := ctx.ChangShengSchool.Mu(yw); mu >= 0 {
+		if errDay == nil && dayZ == mu {
+			add(mu.Chong(), "入日墓, 逢冲墓之日")
+		}
+		if bz == mu {
+			add(mu.Chong(), "动而化墓, 逢冲墓之日")
+		}
+	}
+
+	if len(events) == 0 {
+		if strings.Contains(strength, "强") {
+			add(yz.Chong(), "静而旺相, 逢冲之日")
+		} else {
+			add(yz, "静而休囚, 待值日值月旺相之时")
+		}
+	}
+
+	if !ctx.Date.IsZero() {
+		for i := range events {
+			z, _ := ParseZhi(events[i].Zhi)
+			events[i].Days = NextZhiDays(ctx.Date, z, timingDays)
+			events[i].Months = NextZhiMonths(ctx.Date, z, timingMonths)
+		}
+	}
+	return events
+}
@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPredictTiming_KongAndPo(t *testing.T) {
+	// 壬午 旬空 (午未空) 又逢子月月破
+	ctx := AnalysisContext{
+		DayZhi:     "寅",
+		MonthZhi:   "子",
+		DayXunKong: "午未",
+		Date:       time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+	events := PredictTiming(GuaInfo{Ganzhi: "壬午"}, nil, false, "弱", ctx)
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %v", events)
+	}
+	if events[0].Zhi != "午" || len(events[0].Reasons) != 2 {
+		t.Errorf("expected 午 with 旬空 and 月破 reasons, got %+v", events[0])
+	}
+	if events[1].Zhi != "子" {
+		t.Errorf("expected 冲空 event on 子, got %+v", events[1])
+	}
+
+	for _, d := range events[0].Days {
+		if !d.After(ctx.Date) || lunarAt(d).GetDayZhi() != "午" {
+			t.Errorf("day %s is not a later 午 day", d.Format("2006-01-02"))
+		}
+	}
+	if len(events[0].Days) != timingDays || len(events[0].Months) != timingMonths {
+		t.Fatalf("unexpected dates: %+v", events[0])
+	}
+	m := events[0].Months[0]
+	if monthZhiBy(m) != "午" || monthZhiBy(m.AddDate(0, 0, -1)) == "午" {
+		t.Errorf("month start %s is not the first day of 午 month", m.Format("2006-01-02"))
+	}
+}
+
+func TestNextZhiMonths_LateJie(t *testing.T) {
+	// 小暑 2024-07-06 22:20 交节, 未月自 7 月 6 日起, 非次日
+	got := NextZhiMonths(time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC), ZhiWei, 1)
+	want := time.Date(2024, 7, 6, 0, 0, 0, 0, time.UTC)
+	if len(got) != 1 || got[0].Format("2006-01-02") != want.Format("2006-01-02") {
+		t.Errorf("未月 = %v, want %s", got, want.Format("2006-01-02"))
+	}
+
+	// 交节当日 (大雪 2024-12-06 23:17) 起卦于交节之前, 子月即自当日起
+	got = NextZhiMonths(time.Date(2024, 12, 6, 9, 0, 0, 0, time.UTC), ZhiZi, 1)
+	if len(got) != 1 || got[0].Format("2006-01-02") != "2024-12-06" {
+		t.Errorf("子月 = %v, want 2024-12-06", got)
+	}
+}
+
+func TestPredictTiming_MovingAndTomb(t *testing.T) {
+	ctx := AnalysisContext{DayZhi: "戌", MonthZhi: "寅", DayXunKong: "子丑"}
+
+	// 丙午 发动化 丙戌: 动爻值日, 火墓在戌 (日墓、化墓), 逢辰日冲墓
+	events := PredictTiming(GuaInfo{Ganzhi: "丙午"}, &GuaInfo{Ganzhi: "丙戌"}, true, "强", ctx)
+	want := map[string]bool{"午": false, "辰": false}
+	for _, e := range events {
+		if _, ok := want[e.Zhi]; ok {
+			want[e.Zhi] = true
+		}
+		if len(e.Days) != 0 {
+			t.Error("expected no dates without cast date")
+		}
+	}
+	for zhi, found := range want {
+		if !found {
+			t.Errorf("expected event on %s, got %v", zhi, events)
+		}
+	}
+
+	// 静而旺, 无空破合冲: 逢冲之日
+	events = PredictTiming(GuaInfo{Ganzhi: "甲寅"}, nil, false, "强", AnalysisContext{DayZhi: "亥", MonthZhi: "卯"})
+	if len(events) == 0 {
+		t.Fatal("expected events")
+	}
+	// 寅亥合: 合处逢冲 -> 申
+	if events[0].Zhi != "申" {
+		t.Errorf("expected 申, got %v", events)
+	}
+}
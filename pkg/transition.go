@@ -0,0 +1,76 @@
+package pkg
+
+import "fmt"
+
+// GuaTransition 本卦至变卦的卦变格局
+type GuaTransition struct {
+	Pattern     string // 格局名, 如 "六冲变六合", "化游魂", "宫变"
+	Description string // 断语
+}
+
+// ClassifyTransition 比较本卦与变卦, 归纳卦变格局
+// 涵盖六冲六合之变、游魂归魂之变与宫位之变; 无动爻时返回 nil。
+func ClassifyTransition(benHex, bianHex string) []GuaTransition {
+	if len(benHex) != 6 || len(bianHex) != 6 || benHex == bianHex {
+		return nil
+	}
+
+	var transitions []GuaTransition
+	add := func(pattern, format string, args ...interface{}) {
+		transitions = append(transitions, GuaTransition{Pattern: pattern, Description: fmt.Sprintf(format, args...)})
+	}
+
+	// 1. 六冲六合之变
+	benHe, benChong := CheckGuaType(benHex)
+	bianHe, bianChong := CheckGuaType(bianHex)
+	switch {
+	case benChong && bianHe:
+		add("六冲变六合", "六冲变六合: 先散后聚, 先难后易, 事虽阻滞终可成")
+	case benHe && bianChong:
+		add("六合变六冲", "六合变六冲: 先聚后散, 始合终离, 事虽有成终难久")
+	case benChong && bianChong:
+		add("六冲变六冲", "六冲变六冲: 冲而又冲, 涣散不聚, 事难成 (近病则愈)")
+	case benHe && bianHe:
+		add("六合变六合", "六合变六合: 合而又合, 事多顺遂, 唯久病、官非难解")
+	}
+
+	// 2. 游魂归魂之变
+	benPalace, benIndex, okBen := GetGuaPalace(DetermineGuaName(benHex))
+	bianPalace, bianIndex, okBian := GetGuaPalace(DetermineGuaName(bianHex))
+	if !okBen || !okBian {
+		return transitions
+	}
+	switch {
+	case benIndex == 6 && bianIndex == 7:
+		add("游魂化归魂", "游魂化归魂: 去而复返, 游移之事终归原处")
+	case benIndex == 7 && bianIndex == 6:
+		add("归魂化游魂", "归魂化游魂: 方定又动, 心意反复, 难守本位")
+	case bianIndex == 6:
+		add("化游魂", "化游魂: 心神不定, 主变迁、远行, 事多游移")
+	case bianIndex == 7:
+		add("化归魂", "化归魂: 主归还、回头, 行人将归, 事归本处")
+	}
+
+	// 3. 宫位之变 (以变宫五行论本宫之生克)
+	if benPalace == bianPalace {
+		add("本宫不变", "变卦仍属%s宫, 事不出本位", GetPalaceName(benPalace))
+		return transitions
+	}
+	benWuXing := GetPalaceWuXing(benPalace)
+	bianWuXing := GetPalaceWuXing(bianPalace)
+	relation, verdict := "本宫与变宫比和", "事势平稳"
+	switch {
+	case IsSheng(bianWuXing, benWuXing):
+		relation, verdict = "变宫生本宫", "吉, 得助而成"
+	case IsKe(bianWuXing, benWuXing):
+		relation, verdict = "变宫克本宫", "凶, 后势受制"
+	case IsSheng(benWuXing, bianWuXing):
+		relation, verdict = "本宫生变宫", "耗, 劳而少获"
+	case IsKe(benWuXing, bianWuXing):
+		relation, verdict = "本宫克变宫", "可为, 尚能制之"
+	}
+	add("宫变", "宫变: %s宫(%s) 化 %s宫(%s), %s, 主%s",
+		GetPalaceName(benPalace), benWuXing, GetPalaceName(bianPalace), bianWuXing, relation, verdict)
+
+	return transitions
+}
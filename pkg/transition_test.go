@@ -0,0 +1,44 @@
+package pkg
+
+import "testing"
+
+func TestClassifyTransition(t *testing.T) {
+	tests := []struct {
+		name     string
+		ben      string
+		bian     string
+		patterns []string
+	}{
+		// 乾为天 (六冲) 化 天地否 (六合), 同属乾宫
+		{"冲变合", "111111", "000111", []string{"六冲变六合", "本宫不变"}},
+		// 天地否 (六合) 化 乾为天 (六冲)
+		{"合变冲", "000111", "111111", []string{"六合变六冲", "本宫不变"}},
+		// 乾为天 化 坤为地: 两六冲, 乾宫(金) 化 坤宫(土), 土生金
+		{"冲变冲", "111111", "000000", []string{"六冲变六冲", "宫变"}},
+		// 乾为天 化 火地晋 (乾宫游魂)
+		{"化游魂", "111111", "000101", []string{"化游魂"}},
+		// 火地晋 (游魂) 化 火天大有 (归魂)
+		{"游魂化归魂", "000101", "111101", []string{"游魂化归魂"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyTransition(tt.ben, tt.bian)
+			for _, want := range tt.patterns {
+				found := false
+				for _, tr := range got {
+					if tr.Pattern == want {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("expected pattern %s, got %+v", want, got)
+				}
+			}
+		})
+	}
+
+	if got := ClassifyTransition("111111", "111111"); got != nil {
+		t.Errorf("expected nil without moving lines, got %+v", got)
+	}
+}
@@ -0,0 +1,284 @@
+package pkg
+
+import "fmt"
+
+// Gan 天干
+type Gan int
+
+const (
+	GanJia  Gan = iota // 甲
+	GanYi              // 乙
+	GanBing            // 丙
+	GanDing            // 丁
+	GanWu              // 戊
+	GanJi              // 己
+	GanGeng            // 庚
+	GanXin             // 辛
+	GanRen             // 壬
+	GanGui             // 癸
+)
+
+var ganNames = []string{"甲", "乙", "丙", "丁", "戊", "己", "庚", "辛", "壬", "癸"}
+
+// ParseGan 解析天干, 如 "甲"
+func ParseGan(s string) (Gan, error) {
+	for i, name := range ganNames {
+		if name == s {
+			return Gan(i), nil
+		}
+	}
+	return 0, fmt.Errorf("无效的天干 %q", s)
+}
+
+// Valid 是否为合法天干
+func (g Gan) Valid() bool {
+	return g >= GanJia && g <= GanGui
+}
+
+func (g Gan) String() string {
+	if !g.Valid() {
+		return fmt.Sprintf("Gan(%d)", int(g))
+	}
+	return ganNames[g]
+}
+
+// WuXing 天干五行: 甲乙木 丙丁火 戊己土 庚辛金 壬癸水; 非法天干返回 WuXing(-1)
+func (g Gan) WuXing() WuXing {
+	if !g.Valid() {
+		return WuXing(-1)
+	}
+	return WuXing(int(g) / 2)
+}
+
+// Zhi 地支
+type Zhi int
+
+const (
+	ZhiZi   Zhi = iota // 子
+	ZhiChou            // 丑
+	ZhiYin             // 寅
+	ZhiMao             // 卯
+	ZhiChen            // 辰
+	ZhiSi              // 巳
+	ZhiWu              // 午
+	ZhiWei             // 未
+	ZhiShen            // 申
+	ZhiYou             // 酉
+	ZhiXu              // 戌
+	ZhiHai             // 亥
+)
+
+// 地支五行
+var zhiWuXing = []WuXing{
+	Shui, Tu, Mu, Mu, Tu, Huo,
+	Huo, Tu, Jin, Jin, Tu, Shui,
+}
+
+// 六合所化五行, 以两支中序号较小者为索引
+var zhiHeWuXing = map[Zhi]WuXing{
+	ZhiZi: Tu, ZhiYin: Mu, ZhiMao: Huo, ZhiChen: Jin, ZhiSi: Shui, ZhiWu: Tu,
+}
+
+// ParseZhi 解析地支, 如 "子"
+func ParseZhi(s string) (Zhi, error) {
+	if i := indexOf(dizhi, s); i != -1 {
+		return Zhi(i), nil
+	}
+	return 0, fmt.Errorf("无效的地支 %q", s)
+}
+
+// ZhiFromGanZhi 取干支中的地支, 如 "甲子" -> 子; 也接受单独的地支 "子"
+func ZhiFromGanZhi(ganzhi string) (Zhi, error) {
+	runes := []rune(ganzhi)
+	switch len(runes) {
+	case 1:
+		return ParseZhi(ganzhi)
+	case 2:
+		return ParseZhi(string(runes[1]))
+	}
+	return 0, fmt.Errorf("无效的干支 %q", ganzhi)
+}
+
+// zhiOf 取干支中的地支字符串, 无效时返回 ""
+func zhiOf(ganzhi string) string {
+	z, err := ZhiFromGanZhi(ganzhi)
+	if err != nil {
+		return ""
+	}
+	return z.String()
+}
+
+// Valid 是否为合法地支
+func (z Zhi) Valid() bool {
+	return z >= ZhiZi && z <= ZhiHai
+}
+
+func (z Zhi) String() string {
+	if !z.Valid() {
+		return fmt.Sprintf("Zhi(%d)", int(z))
+	}
+	return dizhi[z]
+}
+
+// WuXing 地支五行; 非法地支返回 WuXing(-1)
+func (z Zhi) WuXing() WuXing {
+	if !z.Valid() {
+		return WuXing(-1)
+	}
+	return zhiWuXing[z]
+}
+
+// Chong 六冲之支: 子午、丑未、寅申、卯酉、辰戌、巳亥; 非法地支返回 Zhi(-1)
+func (z Zhi) Chong() Zhi {
+	if !z.Valid() {
+		return Zhi(-1)
+	}
+	return (z + 6) % 12
+}
+
+// He 六合之支: 子丑、寅亥、卯戌、辰酉、巳申、午未; 非法地支返回 Zhi(-1)
+func (z Zhi) He() Zhi {
+	if !z.Valid() {
+		return Zhi(-1)
+	}
+	return (13 - z) % 12
+}
+
+// HeWuXing 与六合之支所化五行; 非法地支返回 WuXing(-1)
+func (z Zhi) HeWuXing() WuXing {
+	if !z.Valid() {
+		return WuXing(-1)
+	}
+	return zhiHeWuXing[minZhi(z, z.He())]
+}
+
+// Hai 六害之支: 子未、丑午、寅巳、卯辰、申亥、酉戌; 非法地支返回 Zhi(-1)
+func (z Zhi) Hai() Zhi {
+	if !z.Valid() {
+		return Zhi(-1)
+	}
+	return (19 - z) % 12
+}
+
+func minZhi(a, b Zhi) Zhi {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// WuXing 五行, 按相生之序排列
+type WuXing int
+
+const (
+	Mu   WuXing = iota // 木
+	Huo                // 火
+	Tu                 // 土
+	Jin                // 金
+	Shui               // 水
+)
+
+var wuXingNames = []string{"木", "火", "土", "金", "水"}
+
+// ParseWuXing 解析五行, 如 "金"
+func ParseWuXing(s string) (WuXing, error) {
+	for i, name := range wuXingNames {
+		if name == s {
+			return WuXing(i), nil
+		}
+	}
+	return 0, fmt.Errorf("无效的五行 %q", s)
+}
+
+// Valid 是否为合法五行
+func (w WuXing) Valid() bool {
+	return w >= Mu && w <= Shui
+}
+
+func (w WuXing) String() string {
+	if !w.Valid() {
+		return fmt.Sprintf("WuXing(%d)", int(w))
+	}
+	return wuXingNames[w]
+}
+
+// Generates 所生之五行: 木生火, 火生土, 土生金, 金生水, 水生木; 非法五行返回 WuXing(-1)
+func (w WuXing) Generates() WuXing {
+	if !w.Valid() {
+		return WuXing(-1)
+	}
+	return (w + 1) % 5
+}
+
+// Controls 所克之五行: 木克土, 土克水, 水克火, 火克金, 金克木; 非法五行返回 WuXing(-1)
+func (w WuXing) Controls() WuXing {
+	if !w.Valid() {
+		return WuXing(-1)
+	}
+	return (w + 2) % 5
+}
+
+// LiuQin 六亲
+type LiuQin int
+
+const (
+	XiongDi LiuQin = iota // 兄弟: 同我者
+	ZiSun                 // 子孙: 我生者
+	QiCai                 // 妻财: 我克者
+	GuanGui               // 官鬼: 克我者
+	FuMu                  // 父母: 生我者
+)
+
+var liuQinNames = []string{"兄弟", "子孙", "妻财", "官鬼", "父母"}
+
+// ParseLiuQin 解析六亲, 如 "官鬼"
+func ParseLiuQin(s string) (LiuQin, error) {
+	for i, name := range liuQinNames {
+		if name == s {
+			return LiuQin(i), nil
+		}
+	}
+	return 0, fmt.Errorf("无效的六亲 %q", s)
+}
+
+// Valid 是否为合法六亲
+func (q LiuQin) Valid() bool {
+	return q >= XiongDi && q <= FuMu
+}
+
+func (q LiuQin) String() string {
+	if !q.Valid() {
+		return fmt.Sprintf("LiuQin(%d)", int(q))
+	}
+	return liuQinNames[q]
+}
+
+// YuanShen 原神: 生此六亲者, 如官鬼之原神为妻财; 非法六亲返回 LiuQin(-1)
+func (q LiuQin) YuanShen() LiuQin {
+	if !q.Valid() {
+		return LiuQin(-1)
+	}
+	return (q + 4) % 5
+}
+
+// JiShen 忌神: 克此六亲者, 如官鬼之忌神为子孙; 非法六亲返回 LiuQin(-1)
+func (q LiuQin) JiShen() LiuQin {
+	if !q.Valid() {
+		return LiuQin(-1)
+	}
+	return (q + 3) % 5
+}
+
+// ChouShen 仇神: 克原神者, 如官鬼之仇神为兄弟
+func (q LiuQin) ChouShen() LiuQin {
+	return q.YuanShen().JiShen()
+}
+
+// LiuQinOf 以宫五行 (我) 论爻五行的六亲; 任一五行非法时返回 LiuQin(-1)
+func LiuQinOf(palace, line WuXing) LiuQin {
+	if !palace.Valid() || !line.Valid() {
+		return LiuQin(-1)
+	}
+	// 五行按相生之序排列, 相距即六亲之序
+	return LiuQin((line - palace + 5) % 5)
+}
@@ -0,0 +1,130 @@
+package pkg
+
+import "testing"
+
+func TestZhiRelations(t *testing.T) {
+	tests := []struct {
+		zhi    Zhi
+		chong  Zhi
+		he     Zhi
+		hai    Zhi
+		wuXing WuXing
+	}{
+		{ZhiZi, ZhiWu, ZhiChou, ZhiWei, Shui},
+		{ZhiYin, ZhiShen, ZhiHai, ZhiSi, Mu},
+		{ZhiMao, ZhiYou, ZhiXu, ZhiChen, Mu},
+		{ZhiSi, ZhiHai, ZhiShen, ZhiYin, Huo},
+		{ZhiWei, ZhiChou, ZhiWu, ZhiZi, Tu},
+		{ZhiYou, ZhiMao, ZhiChen, ZhiXu, Jin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zhi.String(), func(t *testing.T) {
+			if got := tt.zhi.Chong(); got != tt.chong {
+				t.Errorf("Chong() = %s, want %s", got, tt.chong)
+			}
+			if got := tt.zhi.He(); got != tt.he {
+				t.Errorf("He() = %s, want %s", got, tt.he)
+			}
+			if got := tt.zhi.Hai(); got != tt.hai {
+				t.Errorf("Hai() = %s, want %s", got, tt.hai)
+			}
+			if got := tt.zhi.WuXing(); got != tt.wuXing {
+				t.Errorf("WuXing() = %s, want %s", got, tt.wuXing)
+			}
+		})
+	}
+
+	// 非法地支 (如 timing 中的哨兵 -1) 不 panic, 取不合法之五行与地支
+	for _, z := range []Zhi{-1, 12} {
+		if got := z.WuXing(); got.Valid() {
+			t.Errorf("Zhi(%d).WuXing() = %s, want invalid", int(z), got)
+		}
+		if got := z.HeWuXing(); got.Valid() {
+			t.Errorf("Zhi(%d).HeWuXing() = %s, want invalid", int(z), got)
+		}
+		for name, got := range map[string]Zhi{"Chong": z.Chong(), "He": z.He(), "Hai": z.Hai()} {
+			if got.Valid() {
+				t.Errorf("Zhi(%d).%s() = %s, want invalid", int(z), name, got)
+			}
+		}
+	}
+}
+
+func TestWuXingCycles(t *testing.T) {
+	if Mu.Generates() != Huo || Shui.Generates() != Mu || Tu.Generates() != Jin {
+		t.Error("unexpected generating cycle")
+	}
+	if Mu.Controls() != Tu || Huo.Controls() != Jin || Shui.Controls() != Huo {
+		t.Error("unexpected controlling cycle")
+	}
+	if GanGeng.WuXing() != Jin || GanGui.WuXing() != Shui {
+		t.Error("unexpected Gan WuXing")
+	}
+	// 金宫见火为官鬼, 见土为父母
+	if LiuQinOf(Jin, Huo) != GuanGui || LiuQinOf(Jin, Tu) != FuMu || LiuQinOf(Jin, Mu) != QiCai {
+		t.Error("unexpected LiuQin")
+	}
+
+	// 非法值不回绕成合法的五行或六亲
+	for _, w := range []WuXing{-1, 5} {
+		if w.Generates().Valid() || w.Controls().Valid() {
+			t.Errorf("WuXing(%d) cycles should be invalid", int(w))
+		}
+		if LiuQinOf(Jin, w).Valid() || LiuQinOf(w, Jin).Valid() {
+			t.Errorf("LiuQinOf with WuXing(%d) should be invalid", int(w))
+		}
+	}
+	for _, g := range []Gan{-1, 10} {
+		if got := g.WuXing(); got.Valid() {
+			t.Errorf("Gan(%d).WuXing() = %s, want invalid", int(g), got)
+		}
+	}
+	for _, q := range []LiuQin{-1, 5} {
+		if q.YuanShen().Valid() || q.JiShen().Valid() || q.ChouShen().Valid() {
+			t.Errorf("LiuQin(%d) relations should be invalid", int(q))
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := ParseZhi("甲"); err == nil {
+		t.Error("ParseZhi(甲) expected error")
+	}
+	if _, err := ParseGan("子"); err == nil {
+		t.Error("ParseGan(子) expected error")
+	}
+	if _, err := ParseWuXing("风"); err == nil {
+		t.Error("ParseWuXing(风) expected error")
+	}
+	if _, err := ParseLiuQin("官"); err == nil {
+		t.Error("ParseLiuQin(官) expected error")
+	}
+	for _, s := range []string{"", "甲", "甲子丑", "ab"} {
+		if _, err := ZhiFromGanZhi(s); err == nil {
+			t.Errorf("ZhiFromGanZhi(%q) expected error", s)
+		}
+	}
+	if z, err := ZhiFromGanZhi("壬午"); err != nil || z != ZhiWu {
+		t.Errorf("ZhiFromGanZhi(壬午) = %v, %v", z, err)
+	}
+}
+
+func TestStringWrappers(t *testing.T) {
+	// 非法输入不再 panic, 而是返回零值
+	if CheckXunKong("", "戌亥") {
+		t.Error("CheckXunKong with empty ganzhi should be false")
+	}
+	if CheckJinTui("", "") != "" {
+		t.Error("CheckJinTui with empty ganzhi should be empty")
+	}
+	if GetLiuQin("金", "风") != "未知" {
+		t.Error("GetLiuQin with invalid WuXing should be 未知")
+	}
+	if CheckLiuHe("亥", "寅") != "寅亥合木" || CheckLiuHe("午", "未") != "午未合土" {
+		t.Error("unexpected CheckLiuHe name")
+	}
+	if !IsChong("巳", "亥") || IsChong("巳", "") {
+		t.Error("unexpected IsChong")
+	}
+}
@@ -0,0 +1,223 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 刑的种类
+const (
+	XingSan   = "三刑" // 三支俱全
+	XingXiang = "相刑" // 两支相刑
+	XingZi    = "自刑" // 同支自刑
+)
+
+// Punishment 盘中的一组刑
+type Punishment struct {
+	Kind    string         // 三刑, 相刑, 自刑
+	Name    string         // 如 "寅巳申无恩之刑"、"子卯相刑"、"午午自刑"
+	Members []BranchSource // 参与刑的地支及其来源
+	Lines   []int          // 涉及的爻
+	LiuQin  []string       // 受刑之六亲 (去重, 按出现顺序)
+}
+
+func (p Punishment) String() string {
+	var parts []string
+	for _, m := range p.Members {
+		label := m.Source
+		if m.IsAnDong {
+			label += "/暗动"
+		}
+		if m.LiuQin != "" {
+			label += "," + m.LiuQin
+		}
+		parts = append(parts, fmt.Sprintf("%s(%s)", m.Zhi, label))
+	}
+	s := fmt.Sprintf("%s: %s", p.Name, strings.Join(parts, " "))
+	if len(p.LiuQin) > 0 {
+		s += fmt.Sprintf(", 刑及%s", strings.Join(p.LiuQin, "、"))
+	}
+	return s
+}
+
+// involvesLine 刑是否涉及某爻
+func (p Punishment) involvesLine(index int) bool {
+	for _, l := range p.Lines {
+		if l == index {
+			return true
+		}
+	}
+	return false
+}
+
+// 三刑: 寅巳申无恩之刑, 丑戌未恃势之刑; 子卯无礼之刑只有两支
+var sanXingGroups = []struct {
+	Branches []string
+	Name     string
+}{
+	{[]string{"寅", "巳", "申"}, "无恩之刑"},
+	{[]string{"丑", "戌", "未"}, "恃势之刑"},
+	{[]string{"子", "卯"}, "无礼之刑"},
+}
+
+// 自刑之支
+var ziXingBranches = []string{"辰", "午", "酉", "亥"}
+
+// newPunishment 由参与者构造刑, 汇总爻位与六亲
+func newPunishment(kind, name string, members []BranchSource) Punishment {
+	p := Punishment{Kind: kind, Name: name, Members: members}
+	seen := make(map[string]bool)
+	for _, m := range members {
+		if m.Line >= 0 && !m.IsBian {
+			p.Lines = append(p.Lines, m.Line)
+		}
+		if m.LiuQin != "" && !seen[m.LiuQin] {
+			seen[m.LiuQin] = true
+			p.LiuQin = append(p.LiuQin, m.LiuQin)
+		}
+	}
+	return p
+}
+
+// anyActive 刑须有日月或动爻参与, 静爻之间不论
+func anyActive(members []BranchSource) bool {
+	for _, m := range members {
+		if m.Active() {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifies 刑须有有力者参与, 且须刑及卦中之爻; 仅日月与变爻相刑不论
+func qualifies(members []BranchSource) bool {
+	if !anyActive(members) {
+		return false
+	}
+	for _, m := range members {
+		if m.Line >= 0 && !m.IsBian {
+			return true
+		}
+	}
+	return false
+}
+
+// sameLine 是否为一爻与其所化之变爻, 本爻与自身变爻不论刑
+func sameLine(a, b BranchSource) bool {
+	return a.Line >= 0 && a.Line == b.Line && a.IsBian != b.IsBian
+}
+
+// pickBranch 取某地支的来源, 多处出现时以有力者为先
+func pickBranch(branches []BranchSource, zhi string) (BranchSource, bool) {
+	found := false
+	var picked BranchSource
+	for _, b := range branches {
+		if b.Zhi != zhi {
+			continue
+		}
+		if b.Active() {
+			return b, true
+		}
+		if !found {
+			picked, found = b, true
+		}
+	}
+	return picked, found
+}
+
+// pickMembers 为各地支各取一个来源, 不取一爻与其自身变爻并列;
+// 以取得支数最多者为先, 其次有力者最多, 再次按盘中顺序。
+func pickMembers(branches []BranchSource, targets []string) []BranchSource {
+	var best []BranchSource
+	bestActive := -1
+	var walk func(i int, picked []BranchSource, active int)
+	walk = func(i int, picked []BranchSource, active int) {
+		if i == len(targets) {
+			if len(picked) > len(best) || (len(picked) == len(best) && active > bestActive) {
+				best, bestActive = append([]BranchSource(nil), picked...), active
+			}
+			return
+		}
+	next:
+		for _, b := range branches {
+			if b.Zhi != targets[i] {
+				continue
+			}
+			for _, p := range picked {
+				if sameLine(b, p) {
+					continue next
+				}
+			}
+			n := active
+			if b.Active() {
+				n++
+			}
+			walk(i+1, append(picked, b), n)
+		}
+		walk(i+1, picked, active)
+	}
+	walk(0, nil, 0)
+	return best
+}
+
+// DetectPunishments 通盘检查刑: 寅巳申、丑戌未三支俱全为三刑, 仅见两支 (及子卯) 为相刑,
+// 辰午酉亥两见为自刑。刑须有日月、动爻、变爻或暗动之爻参与, 且至少涉及一个卦爻;
+// 一爻与其自身所化之变爻不相刑。
+func DetectPunishments(branches []BranchSource) []Punishment {
+	var result []Punishment
+
+	for _, g := range sanXingGroups {
+		members := pickMembers(branches, g.Branches)
+
+		if len(members) == 3 {
+			if qualifies(members) {
+				name := strings.Join(g.Branches, "") + g.Name
+				result = append(result, newPunishment(XingSan, name, members))
+			}
+			continue
+		}
+		if len(members) == 2 && qualifies(members) {
+			name := CheckXing(members[0].Zhi, members[1].Zhi)
+			result = append(result, newPunishment(XingXiang, name, members))
+		}
+	}
+
+	for _, zhi := range ziXingBranches {
+		var members []BranchSource
+	outer:
+		for _, b := range branches {
+			if b.Zhi != zhi {
+				continue
+			}
+			for _, m := range members {
+				if sameLine(b, m) {
+					continue outer
+				}
+			}
+			members = append(members, b)
+		}
+		if len(members) >= 2 && qualifies(members) {
+			result = append(result, newPunishment(XingZi, zhi+zhi+"自刑", members))
+		}
+	}
+	return result
+}
+
+// xingRule 通盘论刑: 刑主伤损, 不论旺衰, 涉及用神者为凶
+func xingRule(c *Chart) []Finding {
+	result := c.Result
+	var findings []Finding
+
+	punishments := DetectPunishments(c.Branches())
+	result.Punishments = punishments
+	for _, p := range punishments {
+		f := Finding{Kind: FindingKind(p.Kind), Subject: p.Name, Lines: p.Lines, Text: p.String()}
+		if p.involvesLine(result.YongShenIndex) && !c.IsFuShen {
+			f.Subject = "用神"
+			f.Direction = Unfavorable
+			f.Text += " (用神受刑)"
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
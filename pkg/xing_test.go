@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectPunishments(t *testing.T) {
+	day := func(z string) BranchSource { return BranchSource{Zhi: z, Source: "日建", Line: -1, IsDay: true} }
+	line := func(z string, i int, q string) BranchSource {
+		return BranchSource{Zhi: z, Source: yaoPositions[i], Line: i, LiuQin: q}
+	}
+	moving := func(z string, i int, q string) BranchSource {
+		b := line(z, i, q)
+		b.IsDong = true
+		return b
+	}
+	month := func(z string) BranchSource { return BranchSource{Zhi: z, Source: "月建", Line: -1, IsMonth: true} }
+	bian := func(z string, i int, q string) BranchSource {
+		return BranchSource{Zhi: z, Source: yaoPositions[i] + "变", Line: i, LiuQin: q, IsBian: true}
+	}
+
+	tests := []struct {
+		name     string
+		branches []BranchSource
+		want     []string // Name
+		liuQin   []string // 首组所刑六亲
+	}{
+		{"寅巳申三刑", []BranchSource{day("寅"), line("巳", 1, "官鬼"), line("申", 4, "兄弟")}, []string{"寅巳申无恩之刑"}, []string{"官鬼", "兄弟"}},
+		{"静爻不论", []BranchSource{line("寅", 0, "妻财"), line("巳", 1, "官鬼"), line("申", 4, "兄弟")}, nil, nil},
+		{"丑戌相刑", []BranchSource{moving("丑", 2, "父母"), line("戌", 5, "父母")}, []string{"丑戌相刑"}, []string{"父母"}},
+		{"子卯相刑", []BranchSource{day("子"), line("卯", 0, "妻财")}, []string{"子卯相刑"}, []string{"妻财"}},
+		{"午午自刑", []BranchSource{day("午"), line("午", 3, "官鬼")}, []string{"午午自刑"}, []string{"官鬼"}},
+		{"酉一见不为自刑", []BranchSource{day("酉"), line("卯", 3, "官鬼")}, nil, nil},
+		{"本爻化自身不为自刑", []BranchSource{moving("午", 3, "官鬼"), bian("午", 3, "官鬼")}, nil, nil},
+		{"本爻化自身不相刑", []BranchSource{moving("子", 2, "妻财"), bian("卯", 2, "兄弟")}, nil, nil},
+		{"变爻刑他爻", []BranchSource{moving("子", 2, "妻财"), bian("卯", 2, "兄弟"), line("子", 5, "妻财")}, []string{"子卯相刑"}, []string{"妻财", "兄弟"}},
+		{"日月相刑不论", []BranchSource{day("子"), month("卯"), line("午", 0, "官鬼")}, nil, nil},
+		{"日月自刑不论", []BranchSource{day("辰"), month("辰"), line("午", 0, "官鬼")}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectPunishments(tt.branches)
+			var names []string
+			for _, p := range got {
+				names = append(names, p.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Fatalf("names = %v, want %v", names, tt.want)
+			}
+			if len(got) > 0 && !reflect.DeepEqual(got[0].LiuQin, tt.liuQin) {
+				t.Errorf("LiuQin = %v, want %v", got[0].LiuQin, tt.liuQin)
+			}
+		})
+	}
+}
+
+func TestAnalyze_Punishments(t *testing.T) {
+	// 乾为天寅日: 寅(日建) 刑 五爻壬申
+	ctx := qianCareerContext()
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if !result.HasFinding(FindingXiangXing, "寅申相刑") {
+		t.Errorf("expected 寅申相刑, got %v", result.Punishments)
+	}
+
+	// 午日: 日辰与用神四爻壬午自刑
+	ctx.DayZhi = "午"
+	result, err = Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	found := false
+	for _, f := range result.FindingsOf(FindingZiXing) {
+		if f.Subject == "用神" && f.Direction == Unfavorable {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected 用神 午午自刑, got %v", result.FindingsOf(FindingZiXing))
+	}
+}
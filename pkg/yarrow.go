@@ -0,0 +1,92 @@
+package pkg
+
+import "fmt"
+
+// YarrowChange 大衍筮法中的一变
+type YarrowChange struct {
+	Stalks         int // 本变开始时的蓍草数
+	Left           int // 分而为二后左手 (象天) 的数目
+	Right          int // 挂一后右手 (象地) 余下的数目
+	LeftRemainder  int // 左手揲之以四的余数 (1-4)
+	RightRemainder int // 右手揲之以四的余数 (1-4)
+	Removed        int // 本变去掉的数目 (挂一 + 两手余数)
+}
+
+// YarrowLine 三变成一爻
+type YarrowLine struct {
+	Changes [3]YarrowChange
+	Value   int // 6=老阴 7=少阳 8=少阴 9=老阳
+}
+
+// yarrowSplitWindow 分二时左手取数的范围宽度
+// 左手数在蓍草之半附近的连续 16 个数中均匀选取, 16 为 4 的倍数,
+// 因此揲四的余数严格均匀, 三变所得爻值的概率与古法一致:
+// 6=1/16, 7=5/16, 8=7/16, 9=3/16。
+const yarrowSplitWindow = 16
+
+// castYarrowLine 以大衍之数五十, 其用四十有九, 三变成一爻
+func castYarrowLine(c Caster) (YarrowLine, error) {
+	var line YarrowLine
+	stalks := 49
+
+	for i := range line.Changes {
+		offset, err := c.Intn(yarrowSplitWindow)
+		if err != nil {
+			return YarrowLine{}, err
+		}
+
+		// 分而为二以象两, 挂一以象三
+		left := stalks/2 - yarrowSplitWindow/2 + offset
+		right := stalks - left - 1
+
+		// 揲之以四以象四时, 归奇于扐以象闰
+		leftRem := left % 4
+		if leftRem == 0 {
+			leftRem = 4
+		}
+		rightRem := right % 4
+		if rightRem == 0 {
+			rightRem = 4
+		}
+
+		removed := 1 + leftRem + rightRem
+		line.Changes[i] = YarrowChange{
+			Stalks:         stalks,
+			Left:           left,
+			Right:          right,
+			LeftRemainder:  leftRem,
+			RightRemainder: rightRem,
+			Removed:        removed,
+		}
+		stalks -= removed
+	}
+
+	// 余策 36/32/28/24, 以四除之得 9/8/7/6
+	line.Value = stalks / 4
+	if stalks%4 != 0 || line.Value < 6 || line.Value > 9 {
+		return YarrowLine{}, fmt.Errorf("蓍草余数异常: %d", stalks)
+	}
+	return line, nil
+}
+
+// CastYarrow 大衍筮法起卦, 十八变成卦 (自初爻至上爻)
+// 返回卦与每一爻的三变过程, 随机源记录在卦中。
+func CastYarrow(c Caster) (Gua, []YarrowLine, error) {
+	lines := make([]YarrowLine, 6)
+	values := make([]int, 6)
+	for i := range lines {
+		line, err := castYarrowLine(c)
+		if err != nil {
+			return Gua{}, nil, err
+		}
+		lines[i] = line
+		values[i] = line.Value
+	}
+
+	gua, err := GenerateGuaFromLines(values)
+	if err != nil {
+		return Gua{}, nil, err
+	}
+	gua.Source = c.Source()
+	return gua, lines, nil
+}
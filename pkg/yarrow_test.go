@@ -0,0 +1,87 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+)
+
+// sequenceCaster 按给定序列返回随机数, 用于穷举测试
+type sequenceCaster struct {
+	values []int
+	pos    int
+}
+
+func (c *sequenceCaster) Intn(n int) (int, error) {
+	v := c.values[c.pos%len(c.values)] % n
+	c.pos++
+	return v, nil
+}
+
+func (c *sequenceCaster) Source() string { return "sequence" }
+
+func TestCastYarrowLine_ExactProbabilities(t *testing.T) {
+	// 穷举三变的全部 16^3 种分法, 爻值分布应严格为 1:5:7:3 (/16)
+	counts := map[int]int{}
+	for a := 0; a < yarrowSplitWindow; a++ {
+		for b := 0; b < yarrowSplitWindow; b++ {
+			for c := 0; c < yarrowSplitWindow; c++ {
+				line, err := castYarrowLine(&sequenceCaster{values: []int{a, b, c}})
+				if err != nil {
+					t.Fatalf("castYarrowLine failed: %v", err)
+				}
+				counts[line.Value]++
+			}
+		}
+	}
+
+	total := yarrowSplitWindow * yarrowSplitWindow * yarrowSplitWindow
+	want := map[int]int{6: total / 16, 7: total * 5 / 16, 8: total * 7 / 16, 9: total * 3 / 16}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("value distribution = %v, want %v", counts, want)
+	}
+}
+
+func TestCastYarrowLine_Changes(t *testing.T) {
+	line, err := castYarrowLine(&sequenceCaster{values: []int{0, 0, 0}})
+	if err != nil {
+		t.Fatalf("castYarrowLine failed: %v", err)
+	}
+
+	// 第一变: 49 -> 左16 右32, 余4+4, 去9
+	first := line.Changes[0]
+	if first.Stalks != 49 || first.Left != 16 || first.Right != 32 || first.Removed != 9 {
+		t.Errorf("first change = %+v", first)
+	}
+
+	stalks := 49
+	for i, ch := range line.Changes {
+		if ch.Stalks != stalks {
+			t.Errorf("change %d starts with %d stalks, want %d", i+1, ch.Stalks, stalks)
+		}
+		if ch.Left+ch.Right+1 != ch.Stalks {
+			t.Errorf("change %d: left %d + right %d + 1 != %d", i+1, ch.Left, ch.Right, ch.Stalks)
+		}
+		stalks -= ch.Removed
+	}
+	if stalks/4 != line.Value {
+		t.Errorf("Value = %d, remaining stalks %d", line.Value, stalks)
+	}
+}
+
+func TestCastYarrow(t *testing.T) {
+	gua, lines, err := CastYarrow(NewSeededCaster(7))
+	if err != nil {
+		t.Fatalf("CastYarrow failed: %v", err)
+	}
+	if len(lines) != 6 || len(gua.Lines) != 6 {
+		t.Fatalf("expected 6 lines, got %d/%d", len(lines), len(gua.Lines))
+	}
+	for i, line := range lines {
+		if gua.Lines[i] != line.Value {
+			t.Errorf("Lines[%d] = %d, want %d", i, gua.Lines[i], line.Value)
+		}
+	}
+	if gua.Source != "seed:7" {
+		t.Errorf("Source = %q, want seed:7", gua.Source)
+	}
+}
@@ -0,0 +1,213 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// YongShenShiYao 以世爻为用神 (自占), 非六亲
+const YongShenShiYao = "世爻"
+
+// shiYingMark 以世爻或应爻为用神时返回 "世" 或 "应", 否则为空
+func shiYingMark(yongShen string) string {
+	switch yongShen {
+	case YongShenShiYao:
+		return "世"
+	case YongShenYingYao:
+		return "应"
+	}
+	return ""
+}
+
+// LiangXianRule 用神两现 (多现) 时的取舍法
+type LiangXianRule int
+
+const (
+	LiangXianDefault LiangXianRule = iota // 取持世、发动、临月日、临应者, 皆无则取先见者
+	LiangXianKongPo                       // 先取旬空、月破者 (空破之爻待出空填实而应), 再依默认次序
+)
+
+var liangXianNames = []string{"default", "kongpo"}
+
+func (r LiangXianRule) String() string {
+	if r < 0 || int(r) >= len(liangXianNames) {
+		return ""
+	}
+	return liangXianNames[r]
+}
+
+// ParseLiangXianRule 解析两现取舍法: "default" 或 "kongpo" (亦可 "空破")
+func ParseLiangXianRule(s string) (LiangXianRule, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "default", "默认":
+		return LiangXianDefault, nil
+	case "kongpo", "空破":
+		return LiangXianKongPo, nil
+	}
+	return 0, fmt.Errorf("无效的两现取舍法 %q, 应为 default 或 kongpo", s)
+}
+
+// YongShenCandidate 取用神时的一个候选爻及取舍缘由
+type YongShenCandidate struct {
+	Index    int    // 爻位索引 (0-5)
+	Position string // 爻位
+	LiuQin   string
+	Ganzhi   string
+	FuShen   bool   // 本卦不现, 伏于此爻之下
+	Chosen   bool   // 是否取为用神
+	Reason   string // 取或不取的缘由
+}
+
+func (c YongShenCandidate) String() string {
+	mark := "不取"
+	if c.Chosen {
+		mark = "取"
+	}
+	return fmt.Sprintf("%s %s %s: %s, %s", c.Position, c.LiuQin, c.Ganzhi, mark, c.Reason)
+}
+
+// yongShenStep 多现时的一条取舍次序
+type yongShenStep struct {
+	Label string // 如 "持世"
+	Desc  string // 取用说明, 如 "持世之爻"
+	Match func(i int) bool
+}
+
+// yongShenSteps 依两现取舍法给出取用次序
+func yongShenSteps(ctx AnalysisContext, guaInfo []GuaInfo) []yongShenStep {
+	zhi := func(i int) string { return zhiOf(guaInfo[i].Ganzhi) }
+	steps := []yongShenStep{
+		{"持世", "持世之爻", func(i int) bool { return guaInfo[i].ShiYing == "世" }},
+		{"发动", "发动之爻", func(i int) bool { return len(ctx.Changed) > i && ctx.Changed[i] }},
+		{"临月建", "临月建之爻", func(i int) bool { return zhi(i) == ctx.MonthZhi }},
+		{"临日辰", "临日辰之爻", func(i int) bool { return zhi(i) == ctx.DayZhi }},
+		{"临应", "临应爻", func(i int) bool { return guaInfo[i].ShiYing == "应" }},
+	}
+	if ctx.LiangXian == LiangXianKongPo {
+		steps = append([]yongShenStep{
+			{"旬空", "旬空之爻", func(i int) bool { return CheckXunKong(guaInfo[i].Ganzhi, ctx.DayXunKong) }},
+			{"月破", "月破之爻", func(i int) bool { return IsChong(ctx.MonthZhi, zhi(i)) }},
+		}, steps...)
+	}
+	return steps
+}
+
+// SelectYongShen 在本卦中取用神之爻
+// 指定爻位 (ctx.YongShenLine) 时径取该爻; 否则取六亲为 yongShen 之爻, 多现时依 ctx.LiangXian 取舍,
+// 本卦不现则取伏神。返回所取爻位、全部候选及其缘由, 以及记录取舍的断卦要素。
+func SelectYongShen(ctx AnalysisContext, guaInfo []GuaInfo, yongShen string) (int, []YongShenCandidate, []Finding, error) {
+	var candidates []YongShenCandidate
+	var findings []Finding
+	newCandidate := func(i int) YongShenCandidate {
+		return YongShenCandidate{Index: i, Position: guaInfo[i].Position, LiuQin: guaInfo[i].LiuQin, Ganzhi: guaInfo[i].Ganzhi}
+	}
+
+	var indexes []int
+	mark := shiYingMark(yongShen)
+	for i, info := range guaInfo {
+		if info.LiuQin == yongShen || (mark != "" && info.ShiYing == mark) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	// 指定用神
+	if ctx.YongShenLine != 0 {
+		pinned := ctx.YongShenLine - 1
+		if pinned < 0 || pinned >= len(guaInfo) {
+			return -1, nil, nil, fmt.Errorf("无效的用神爻位 %d, 应为 1-%d", ctx.YongShenLine, len(guaInfo))
+		}
+		pinnedSeen := false
+		for _, i := range indexes {
+			c := newCandidate(i)
+			if i == pinned {
+				c.Chosen, c.Reason, pinnedSeen = true, "指定为用神", true
+			} else {
+				c.Reason = fmt.Sprintf("已指定%s为用神", guaInfo[pinned].Position)
+			}
+			candidates = append(candidates, c)
+		}
+		if !pinnedSeen {
+			c := newCandidate(pinned)
+			c.Chosen, c.Reason = true, fmt.Sprintf("指定为用神 (六亲 %s, 非 %s)", guaInfo[pinned].LiuQin, yongShen)
+			candidates = append(candidates, c)
+		}
+		findings = append(findings, Finding{Kind: FindingYongShen, Subject: "用神", Lines: []int{pinned},
+			Text: fmt.Sprintf("指定 %s %s 为用神", guaInfo[pinned].Position, guaInfo[pinned].LiuQin)})
+		return pinned, candidates, findings, nil
+	}
+
+	switch len(indexes) {
+	case 0:
+		// Not found in Ben Gua -> Check Fu Shen (Hidden Spirit)
+		for i, info := range guaInfo {
+			if strings.Contains(info.FuShen, yongShen) {
+				c := newCandidate(i)
+				c.FuShen, c.Chosen, c.Reason = true, true, fmt.Sprintf("用神不现, 伏于此爻下 (%s)", info.FuShen)
+				findings = append(findings, Finding{Kind: FindingYongShen, Subject: "伏神", Lines: []int{i},
+					Text: fmt.Sprintf("用神 %s 不现，伏藏于 %s 下 (爻位: %s)", yongShen, info.LiuQin, info.Position)})
+				return i, []YongShenCandidate{c}, findings, nil
+			}
+		}
+		return -1, nil, nil, fmt.Errorf("用神 %s 不现且未伏藏", yongShen)
+	case 1:
+		c := newCandidate(indexes[0])
+		c.Chosen, c.Reason = true, "唯一出现"
+		if mark != "" {
+			c.Reason = "自占以世爻为用神"
+			if yongShen == YongShenYingYao {
+				c.Reason = "代占他人以应爻为用神"
+			}
+			findings = append(findings, Finding{Kind: FindingYongShen, Subject: "用神", Lines: []int{indexes[0]},
+				Text: fmt.Sprintf("%s (爻位: %s, %s %s)", c.Reason, guaInfo[indexes[0]].Position, guaInfo[indexes[0]].LiuQin, guaInfo[indexes[0]].Ganzhi)})
+		}
+		return indexes[0], []YongShenCandidate{c}, findings, nil
+	}
+
+	// Multiple candidates - apply priority rules in order
+	steps := yongShenSteps(ctx, guaInfo)
+	chosen, decisive := -1, -1
+	for s, step := range steps {
+		for _, i := range indexes {
+			if step.Match(i) {
+				chosen, decisive = i, s
+				break
+			}
+		}
+		if chosen >= 0 {
+			break
+		}
+	}
+
+	var text string
+	if chosen >= 0 {
+		text = fmt.Sprintf("出现多个 %s，取%s (爻位: %s)", yongShen, steps[decisive].Desc, guaInfo[chosen].Position)
+	} else {
+		// Fallback: Take the first one
+		chosen, decisive = indexes[0], len(steps)
+		text = fmt.Sprintf("出现多个 %s，取第一个 (爻位: %s)", yongShen, guaInfo[chosen].Position)
+	}
+	findings = append(findings, Finding{Kind: FindingYongShen, Subject: "用神", Lines: []int{chosen}, Text: text})
+
+	// 逐一说明取舍: 前序各条皆不合, 至决定之条或同合而后见
+	var failed []string
+	for _, step := range steps[:decisive] {
+		failed = append(failed, "不"+step.Label)
+	}
+	for _, i := range indexes {
+		c := newCandidate(i)
+		switch {
+		case i == chosen && decisive < len(steps):
+			c.Chosen, c.Reason = true, steps[decisive].Label
+		case i == chosen:
+			c.Chosen, c.Reason = true, fmt.Sprintf("皆%s, 取先见者", strings.Join(failed, "、"))
+		case decisive < len(steps) && steps[decisive].Match(i):
+			c.Reason = fmt.Sprintf("同为%s, 取先见者", steps[decisive].Label)
+		case decisive < len(steps):
+			c.Reason = strings.Join(append(append([]string(nil), failed...), "不"+steps[decisive].Label), "、")
+		default:
+			c.Reason = "皆不合, 后见"
+		}
+		candidates = append(candidates, c)
+	}
+	return chosen, candidates, findings, nil
+}
@@ -0,0 +1,105 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectYongShen_LiangXian(t *testing.T) {
+	// 乾为天问学业: 父母两现, 三爻甲辰临应, 上爻壬戌持世
+	ctx := qianCareerContext()
+	ctx.Category = CategoryStudy
+	ctx.DayXunKong = "辰巳"
+
+	tests := []struct {
+		name      string
+		rule      LiangXianRule
+		wantIndex int
+		reasons   map[int]string
+	}{
+		{"默认取持世", LiangXianDefault, 5, map[int]string{2: "不持世", 5: "持世"}},
+		{"空破派取旬空", LiangXianKongPo, 2, map[int]string{2: "旬空", 5: "不旬空"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx.LiangXian = tt.rule
+			result, err := Analyze(ctx)
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+			if result.YongShenIndex != tt.wantIndex {
+				t.Errorf("YongShenIndex = %d, want %d", result.YongShenIndex, tt.wantIndex)
+			}
+			if len(result.YongShenCandidates) != 2 {
+				t.Fatalf("expected 2 candidates, got %v", result.YongShenCandidates)
+			}
+			for _, c := range result.YongShenCandidates {
+				if c.Chosen != (c.Index == tt.wantIndex) {
+					t.Errorf("%s: Chosen = %v", c.Position, c.Chosen)
+				}
+				if c.Reason != tt.reasons[c.Index] {
+					t.Errorf("%s: Reason = %q, want %q", c.Position, c.Reason, tt.reasons[c.Index])
+				}
+			}
+		})
+	}
+}
+
+func TestSelectYongShen_Pinned(t *testing.T) {
+	// 问事业而指定二爻甲寅妻财为用神
+	ctx := qianCareerContext()
+	ctx.YongShenLine = 2
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.YongShenIndex != 1 || result.YongShen != "妻财" {
+		t.Errorf("got %s at %d, want 妻财 at 1", result.YongShen, result.YongShenIndex)
+	}
+	var override bool
+	for _, f := range result.FindingsOf(FindingYongShen) {
+		if strings.Contains(f.Text, "改以妻财论") {
+			override = true
+		}
+	}
+	if !override {
+		t.Errorf("expected 用神 override finding, got %v", result.FindingsOf(FindingYongShen))
+	}
+
+	// 四爻官鬼仍列为候选而不取
+	var rejected, chosen int
+	for _, c := range result.YongShenCandidates {
+		if c.Chosen {
+			chosen++
+		} else if c.Index == 3 {
+			rejected++
+		}
+	}
+	if chosen != 1 || rejected != 1 {
+		t.Errorf("unexpected candidates %v", result.YongShenCandidates)
+	}
+
+	ctx.YongShenLine = 7
+	if _, err := Analyze(ctx); err == nil {
+		t.Error("expected error for line 7")
+	}
+
+	// 占病以世爻为用神, 指定上爻 (世) 时仍作世爻论
+	ctx.Category, ctx.YongShenLine = CategoryHealth, 6
+	result, err = Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.YongShenIndex != 5 || result.YongShen != YongShenShiYao {
+		t.Errorf("got %s at %d, want %s at 5", result.YongShen, result.YongShenIndex, YongShenShiYao)
+	}
+}
+
+func TestParseLiangXianRule(t *testing.T) {
+	if r, err := ParseLiangXianRule("空破"); err != nil || r != LiangXianKongPo {
+		t.Errorf("ParseLiangXianRule(空破) = %v, %v", r, err)
+	}
+	if _, err := ParseLiangXianRule("other"); err == nil {
+		t.Error("expected error")
+	}
+}
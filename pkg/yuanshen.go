@@ -0,0 +1,194 @@
+package pkg
+
+import "fmt"
+
+// 用神之外的三种辅神
+const (
+	RoleYuanShen = "原神" // 生用神者
+	RoleJiShen   = "忌神" // 克用神者
+	RoleChouShen = "仇神" // 克原神者
+)
+
+// ShenState 原神、忌神或仇神在卦中的状态
+type ShenState struct {
+	Role     string // 原神, 忌神, 仇神
+	LiuQin   string
+	Index    int // 所取之爻 (0-5), -1 表示卦中不现
+	Ganzhi   string
+	Moving   bool
+	Strength string // 强, 中平, 弱
+	Empty    bool   // 旬空
+	Broken   bool   // 月破或日破
+}
+
+// Present 卦中是否出现
+func (s ShenState) Present() bool {
+	return s.Index >= 0
+}
+
+// Active 动而有力: 发动、不弱、不空不破, 方能生克用神
+func (s ShenState) Active() bool {
+	return s.Present() && s.Moving && s.Strength != "弱" && !s.Empty && !s.Broken
+}
+
+func (s ShenState) String() string {
+	if !s.Present() {
+		return fmt.Sprintf("%s%s不现", s.Role, s.LiuQin)
+	}
+	state := "静"
+	if s.Moving {
+		state = "动"
+	}
+	if s.Empty {
+		state += " 旬空"
+	}
+	if s.Broken {
+		state += " 破"
+	}
+	return fmt.Sprintf("%s%s %s (%s, %s, %s)", s.Role, s.LiuQin, s.Ganzhi, yaoPositions[s.Index], state, s.Strength)
+}
+
+// evaluateShen 在卦中寻找某六亲并评估其状态
+// 多爻同现时取动爻, 同为动静时取较旺者。
+func evaluateShen(c *Chart, role string, q LiuQin) ShenState {
+	best := ShenState{Role: role, LiuQin: q.String(), Index: -1}
+	bestScore := 0
+	for i, info := range c.GuaInfo {
+		if info.LiuQin != q.String() || (i == c.Result.YongShenIndex && !c.IsFuShen) {
+			continue
+		}
+
+		state, score := shenAt(c, role, i)
+		better := !best.Present() ||
+			(state.Moving && !best.Moving) ||
+			(state.Moving == best.Moving && score > bestScore)
+		if better {
+			best, bestScore = state, score
+		}
+	}
+	return best
+}
+
+// findShen 寻某六亲为某神; 他爻不现而用神之爻自持者, 以用神之爻论
+func findShen(c *Chart, role string, q LiuQin) ShenState {
+	s := evaluateShen(c, role, q)
+	if !s.Present() && c.GuaInfo[c.Result.YongShenIndex].LiuQin == q.String() {
+		s, _ = shenAt(c, role, c.Result.YongShenIndex)
+	}
+	s.Role, s.LiuQin = role, q.String()
+	return s
+}
+
+// shenAt 评估第 i 爻作为某神的状态, 并返回其旺衰总分
+func shenAt(c *Chart, role string, i int) (ShenState, int) {
+	info := c.GuaInfo[i]
+	moving := c.IsMoving(i)
+	var bian *GuaInfo
+	if moving && len(c.BianInfo) > i {
+		bian = &c.BianInfo[i]
+	}
+	strength, findings := c.Profile.CalculateStrength(info, bian, moving, c.Ctx.MonthZhi, c.Ctx.DayZhi, c.Ctx.DayXunKong)
+
+	state := ShenState{Role: role, LiuQin: info.LiuQin, Index: i, Ganzhi: info.Ganzhi, Moving: moving, Strength: strength}
+	score := 0
+	for _, f := range findings {
+		score += f.Score
+		switch f.Kind {
+		case FindingXunKong:
+			state.Empty = true
+		case FindingYuePo, FindingRiPo:
+			state.Broken = true
+		}
+	}
+	return state, score
+}
+
+// yongShenLiuQin 用神之六亲; 用神非六亲 (如 "世爻") 时, 以所取之爻的六亲论
+func yongShenLiuQin(result *AnalysisResult) (LiuQin, error) {
+	if q, err := ParseLiuQin(result.YongShen); err == nil {
+		return q, nil
+	}
+	return ParseLiuQin(result.YongShenYao.LiuQin)
+}
+
+// xiangShenRule 寻原神、忌神、仇神, 论其动静旺衰空破, 供吉凶判断参考
+func xiangShenRule(c *Chart) []Finding {
+	result := c.Result
+
+	yongShen, err := yongShenLiuQin(result)
+	if err != nil {
+		return nil
+	}
+
+	result.YuanShen = evaluateShen(c, RoleYuanShen, yongShen.YuanShen())
+	result.JiShen = evaluateShen(c, RoleJiShen, yongShen.JiShen())
+	result.ChouShen = evaluateShen(c, RoleChouShen, yongShen.ChouShen())
+
+	var findings []Finding
+	for _, s := range []ShenState{result.YuanShen, result.JiShen, result.ChouShen} {
+		f := Finding{Kind: shenKind(s.Role), Subject: s.LiuQin, Text: s.String()}
+		if s.Present() {
+			f.Lines = []int{s.Index}
+		}
+		if s.Active() {
+			switch s.Role {
+			case RoleYuanShen:
+				f.Direction = Favorable
+			default:
+				f.Direction = Unfavorable
+			}
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// shenKind 原神、忌神、仇神对应的要素种类
+func shenKind(role string) FindingKind {
+	switch role {
+	case RoleJiShen:
+		return FindingJiShen
+	case RoleChouShen:
+		return FindingChouShen
+	}
+	return FindingYuanShen
+}
+
+// shenAdjustment 原神、忌神、仇神对用神的综合影响 (以旺衰等级计, +1 升一等, -1 降一等)
+func shenAdjustment(shens []ShenState) (int, []string) {
+	var yuan, ji, chou ShenState
+	for _, s := range shens {
+		switch s.Role {
+		case RoleYuanShen:
+			yuan = s
+		case RoleJiShen:
+			ji = s
+		case RoleChouShen:
+			chou = s
+		}
+	}
+
+	delta := 0
+	var notes []string
+	switch {
+	case ji.Active() && yuan.Active():
+		delta++
+		notes = append(notes, "忌神与原神同动, 忌生原、原生用, 连续相生, 反凶为吉")
+	case ji.Active():
+		delta--
+		notes = append(notes, "忌神发动有力, 克伤用神")
+	case yuan.Active() && chou.Active():
+		notes = append(notes, "原神虽动, 被仇神所克, 生用无力")
+	case yuan.Active():
+		delta++
+		notes = append(notes, "原神发动有力, 生扶用神")
+	}
+
+	if yuan.Moving && !yuan.Active() {
+		notes = append(notes, "原神发动而衰弱空破, 不能生用")
+	}
+	if ji.Moving && !ji.Active() {
+		notes = append(notes, "忌神发动而衰弱空破, 不能为害")
+	}
+	return delta, notes
+}
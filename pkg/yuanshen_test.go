@@ -0,0 +1,101 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLiuQin_XiangShen(t *testing.T) {
+	tests := []struct {
+		yong, yuan, ji, chou LiuQin
+	}{
+		{GuanGui, QiCai, ZiSun, XiongDi},
+		{QiCai, ZiSun, XiongDi, FuMu},
+		{FuMu, GuanGui, QiCai, ZiSun},
+		{ZiSun, XiongDi, FuMu, GuanGui},
+		{XiongDi, FuMu, GuanGui, QiCai},
+	}
+	for _, tt := range tests {
+		if got := tt.yong.YuanShen(); got != tt.yuan {
+			t.Errorf("%s.YuanShen() = %s, want %s", tt.yong, got, tt.yuan)
+		}
+		if got := tt.yong.JiShen(); got != tt.ji {
+			t.Errorf("%s.JiShen() = %s, want %s", tt.yong, got, tt.ji)
+		}
+		if got := tt.yong.ChouShen(); got != tt.chou {
+			t.Errorf("%s.ChouShen() = %s, want %s", tt.yong, got, tt.chou)
+		}
+	}
+}
+
+func TestJiXiongLevel_XiangShen(t *testing.T) {
+	active := func(role string) ShenState {
+		return ShenState{Role: role, Index: 0, Moving: true, Strength: "强"}
+	}
+	broken := active(RoleYuanShen)
+	broken.Broken = true
+
+	tests := []struct {
+		name     string
+		strength string
+		shens    []ShenState
+		want     string
+	}{
+		{"无辅神", "弱", nil, "凶"},
+		{"原神动而有力", "弱", []ShenState{active(RoleYuanShen)}, "平"},
+		{"原神月破", "弱", []ShenState{broken}, "凶"},
+		{"原神被仇神克", "中平", []ShenState{active(RoleYuanShen), active(RoleChouShen)}, "平"},
+		{"忌神动", "强", []ShenState{active(RoleJiShen)}, "平"},
+		{"连续相生", "中平", []ShenState{active(RoleYuanShen), active(RoleJiShen)}, "吉"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, notes := jiXiongLevel(tt.strength, tt.shens)
+			if got := (Verdict{Level: level}).Judgment(); got != tt.want {
+				t.Errorf("jiXiongLevel = %s, want %s (%v)", got, tt.want, notes)
+			}
+		})
+	}
+}
+
+func TestAnalyze_XiangShen(t *testing.T) {
+	// 乾为天 二爻甲寅妻财发动 (化天火同人), 问事业: 用神官鬼壬午
+	ctx := qianCareerContext()
+	ctx.BianHexagram = "101111"
+	ctx.Changed = []bool{false, true, false, false, false, false}
+
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if result.YuanShen.LiuQin != "妻财" || result.YuanShen.Index != 1 || !result.YuanShen.Moving {
+		t.Errorf("unexpected 原神: %+v", result.YuanShen)
+	}
+	if result.JiShen.LiuQin != "子孙" || result.JiShen.Index != 0 || result.JiShen.Moving {
+		t.Errorf("unexpected 忌神: %+v", result.JiShen)
+	}
+	if result.ChouShen.LiuQin != "兄弟" || result.ChouShen.Index != 4 {
+		t.Errorf("unexpected 仇神: %+v", result.ChouShen)
+	}
+	if len(result.FindingsOf(FindingYuanShen)) != 1 {
+		t.Error("expected a 原神 finding")
+	}
+}
+
+func TestAnalyze_XiangShenFeiShen(t *testing.T) {
+	// 风地观问子孙: 子孙甲子伏于初爻乙未父母之下, 飞神父母即忌神
+	ctx := qianCareerContext()
+	ctx.GuaHexagram, ctx.BianHexagram = "000011", "000011"
+	ctx.Category = CategoryChildren
+	result, err := Analyze(ctx)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if !strings.Contains(result.YongShenYao.FuShen, "子孙") {
+		t.Fatalf("expected 子孙 伏藏, got %+v", result.YongShenYao)
+	}
+	if result.JiShen.LiuQin != "父母" || result.JiShen.Index != result.YongShenIndex {
+		t.Errorf("忌神 = %s, want 飞神 父母 at %d", result.JiShen, result.YongShenIndex)
+	}
+}